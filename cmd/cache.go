@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// cacheCmd groups subcommands managing the on-disk session cache (see
+// pkg/cache and the root/list commands' --cache-ttl/--no-cache flags).
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk session cache",
+}
+
+var cacheRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Repopulate the on-disk session cache from 1Password",
+	Long: `Invalidates the on-disk session cache and relists sessions from
+1Password for the given --vault/--tags, repopulating it immediately.
+
+Run this from cron, a login hook, or before a demo so a later "list" or
+the root command's interactive picker -- started with a matching
+--cache-ttl -- reads the cache back instantly instead of waiting on
+"op" itself.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vault, err := cmd.Flags().GetString("vault")
+		if err != nil {
+			return err
+		}
+
+		tagsFlag, err := cmd.Flags().GetString("tags")
+		if err != nil {
+			return err
+		}
+
+		if vault == "" {
+			vault = getEnvWithFallback("C8YOP_VAULT", "CYOP_VAULT")
+		}
+		tags := parseTags(tagsFlag)
+
+		store, err := cache.Open()
+		if err != nil {
+			return fmt.Errorf("opening session cache: %w", err)
+		}
+		if err := store.InvalidateAll(); err != nil {
+			return fmt.Errorf("invalidating session cache: %w", err)
+		}
+
+		client := onepassword.NewClient(vault, tags...)
+		client.Cache = store
+		sessions, err := client.List()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Cached %d session(s) for vault %q\n", len(sessions), vault)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheRefreshCmd)
+	rootCmd.AddCommand(cacheCmd)
+	cacheRefreshCmd.Flags().String("vault", "", "Vault name or ID (defaults to C8YOP_VAULT or CYOP_VAULT env var)")
+	cacheRefreshCmd.Flags().String("tags", "", "Tags to filter by (defaults to C8YOP_TAGS or CYOP_TAGS env var, then 'c8y')")
+}
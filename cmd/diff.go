@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// diffCmd compares a local session file against the current state of its
+// matching 1Password item, for teams keeping many Cumulocity tenants in
+// version control -- run it as a pre-commit or CI check to catch a tenant
+// whose vault item has drifted from what's checked in.
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare a local session file to its 1Password item",
+	Long: `Reads a Cumulocity session (as core.CumulocitySession JSON or YAML)
+from --file, or stdin if --file is omitted, and compares it field-by-field
+against the current state of the 1Password item named by the session's
+sessionUri ("op://vault/item").
+
+Use --cache to compare against a locally cached vault listing (see the
+"cache refresh" command) instead of fetching the item from op, so this can
+run offline. --tags must match whatever "cache refresh" was run with
+(defaults to "c8y" either way), since the cache is keyed by vault and tags
+together. Exits non-zero when drift is detected, so it can be used as a
+pre-commit or CI check.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+		useCache, err := cmd.Flags().GetBool("cache")
+		if err != nil {
+			return err
+		}
+
+		tagsFlag, err := cmd.Flags().GetString("tags")
+		if err != nil {
+			return err
+		}
+		tags := parseTags(tagsFlag)
+
+		var input io.Reader = os.Stdin
+		if file != "" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("opening %s: %w", file, err)
+			}
+			defer f.Close()
+			input = f
+		}
+
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("reading session: %w", err)
+		}
+
+		var session core.CumulocitySession
+		if err := json.Unmarshal(data, &session); err != nil {
+			if err := yaml.Unmarshal(data, &session); err != nil {
+				return fmt.Errorf("decoding session as JSON or YAML: %w", err)
+			}
+		}
+
+		if session.SessionURI == "" {
+			return fmt.Errorf("session has no sessionUri (op://vault/item) to diff against")
+		}
+
+		client := onepassword.NewClient("", tags...)
+		if useCache {
+			store, err := cache.Open()
+			if err != nil {
+				return fmt.Errorf("opening session cache: %w", err)
+			}
+			client.Cache = store
+		}
+
+		diff, err := client.Diff(&session, useCache)
+		if err != nil {
+			return err
+		}
+
+		out, err := yaml.Marshal(diff)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+
+		if diff.Drifted() {
+			// Intentional non-error exit: the diff itself is the output, a
+			// CI check just needs the process status to reflect it.
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().String("file", "", "Read the local session from this file instead of stdin")
+	diffCmd.Flags().Bool("cache", false, "Diff against the on-disk session cache instead of fetching from 1Password")
+	diffCmd.Flags().String("tags", "", "Tags the cached listing was built with, same syntax as 'list --tags' (defaults to C8YOP_TAGS or CYOP_TAGS env var, then 'c8y')")
+}
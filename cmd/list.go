@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/output"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core/picker"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
 )
@@ -18,18 +22,42 @@ For direct item retrieval, use the root command with --vault/--item or --uri fla
 By default, sensitive information (passwords, TOTP secrets) is obfuscated in the output.
 Use --reveal to show the actual values.
 
+Non-interactive output:
+- Use --output/-o to choose the format: "table" (the default) launches the
+  interactive picker; any other format skips the picker and streams every
+  matching session instead: "json", "yaml", "jsonpath=<expr>" (e.g. "jsonpath={.items[*].host}"), or
+  "go-template=<text>" (e.g. "go-template={{range .Items}}{{.Host}}
+  {{\"\n\"}}{{end}}"). json/yaml/jsonpath/go-template all render the same
+  {"items": [...], "metadata": {...}} envelope, so scripts can pipe the
+  output into jq/yq or c8y itself
+- When stdout isn't a terminal and --output wasn't given explicitly, "json"
+  is used automatically instead of launching the picker
+- --dump <format> is a stronger version of the above: it always skips the
+  picker, even on a TTY, writing "json", "yaml", "table", or "tsv" (the
+  latter two honor --columns, a comma list of name,host,username,tenant,
+  vault,tags,environment,uri; default "host,username,vault,tags")
+
 Examples:
   # Interactive selection with all sessions (passwords obfuscated)
   c8y-session-1password list
-  
+
   # Show passwords and TOTP secrets in output
   c8y-session-1password list --reveal
-  
+
   # Filter by specific vault
   c8y-session-1password list --vault "Employee"
-  
+
   # Filter by tags with revealed passwords
-  c8y-session-1password list --tags "c8y,production" --reveal`,
+  c8y-session-1password list --tags "c8y,production" --reveal
+
+  # Stream every matching session as JSON instead of picking one
+  c8y-session-1password list --output json
+
+  # Extract just the hosts with jsonpath
+  c8y-session-1password list -o jsonpath={.items[*].host}
+
+  # Script against a plain TSV of host/tenant, skipping the picker outright
+  c8y-session-1password list --dump tsv --columns host,tenant`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		vault, err := cmd.Flags().GetString("vault")
@@ -47,6 +75,46 @@ Examples:
 			return err
 		}
 
+		outputFlag, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		filterMode, err := cmd.Flags().GetString("filter-mode")
+		if err != nil {
+			return err
+		}
+
+		expandHosts, err := cmd.Flags().GetBool("expand-hosts")
+		if err != nil {
+			return err
+		}
+
+		cacheTTL, err := cmd.Flags().GetDuration("cache-ttl")
+		if err != nil {
+			return err
+		}
+
+		noCache, err := cmd.Flags().GetBool("no-cache")
+		if err != nil {
+			return err
+		}
+
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+
+		dumpFlag, err := cmd.Flags().GetString("dump")
+		if err != nil {
+			return err
+		}
+
+		columnsFlag, err := cmd.Flags().GetString("columns")
+		if err != nil {
+			return err
+		}
+
 		// Get default values from environment variables
 		if vault == "" {
 			vault = getEnvWithFallback("C8YOP_VAULT", "CYOP_VAULT")
@@ -56,27 +124,77 @@ Examples:
 		tags := parseTags(tagsFlag)
 
 		client := onepassword.NewClient(vault, tags...)
-		sessions, err := client.List()
+		client.HostResolver = hostResolverFor(expandHosts)
+		client.Cache, client.CacheTTL = cacheFor(cacheTTL, noCache)
+		client.Timeout = timeout
+		sessions, err := client.ListContext(cmd.Context())
 		if err != nil {
 			return err
 		}
 
-		// Always use interactive picker for list command
-		session, err := picker.Pick(sessions)
+		metadata := picker.PickerMetadata{
+			Vaults:     splitAndTrimString(vault),
+			Tags:       tagsSelectorDisplay(tags),
+			FilterMode: filterMode,
+		}
+
+		// --dump always skips the interactive picker, even on a TTY, so
+		// "list --dump json | jq ..." and similar pipelines behave the same
+		// whether or not stdout happens to be a terminal.
+		if dumpFlag != "" {
+			switch dumpFlag {
+			case "json", "yaml":
+				return output.Encode(os.Stdout, output.NewEnvelope(sessions, metadata, reveal), output.Format{Name: dumpFlag})
+			case "table", "tsv":
+				return picker.Dump(os.Stdout, sessions, dumpFlag, splitAndTrimString(columnsFlag))
+			default:
+				return fmt.Errorf("unsupported --dump format: %s (supported: json, yaml, table, tsv)", dumpFlag)
+			}
+		}
+
+		// NoTTY fast-path: with no explicit --output and nothing to render
+		// the picker against, stream JSON instead of failing.
+		if !cmd.Flags().Changed("output") && !stdoutIsTTY() {
+			outputFlag = "json"
+		}
+
+		format := output.ParseFormat(outputFlag)
+		if format.Name != "table" && format.Name != "" {
+			return output.Encode(os.Stdout, output.NewEnvelope(sessions, metadata, reveal), format)
+		}
+
+		// Table mode: the interactive picker.
+		session, err := picker.Pick(sessions, metadata)
 		if err != nil {
 			return err
 		}
 
-		// Populate session details and TOTP from the full session list
-		populateSessionFromList(session, sessions)
+		// Populate session details, TOTP, and OIDC token from the full session list
+		if err := populateSessionFromList(session, sessions, "auto"); err != nil {
+			return err
+		}
 
-		return outputSession(session, reveal)
+		return outputSession(session, reveal, "yaml")
 	},
 }
 
+// stdoutIsTTY reports whether stdout looks like an interactive terminal,
+// mirroring the stdin check the root command uses to detect native
+// messaging mode.
+func stdoutIsTTY() bool {
+	stat, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().String("vault", "", "Vault name or ID (defaults to C8YOP_VAULT or CYOP_VAULT env var)")
-	listCmd.Flags().String("tags", "", "Comma-separated tags to filter by (defaults to C8YOP_TAGS or CYOP_TAGS env var, then 'c8y')")
+	listCmd.Flags().String("tags", "", "Tags to filter by: a label selector (e.g. \"env=prod,!deprecated\" or \"env in (prod,staging)\"), or a plain comma list matching any tag (defaults to C8YOP_TAGS or CYOP_TAGS env var, then 'c8y')")
 	listCmd.Flags().Bool("reveal", false, "Show sensitive information like passwords and TOTP secrets in output")
+	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml, jsonpath=<expr>, or go-template=<text> (overrides the root command's --output for this command)")
+	listCmd.Flags().String("dump", "", "Skip the interactive picker and write every matching session as json, yaml, table, or tsv, even on a TTY")
+	listCmd.Flags().String("columns", "", "Comma-separated columns for --dump table/tsv: name,host,username,tenant,vault,tags,environment,uri (default: host,username,vault,tags)")
 }
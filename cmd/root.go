@@ -1,21 +1,36 @@
 package cmd
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	texttemplate "text/template"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/config"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core/picker"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/selector"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/oidc"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/vaultsecrets"
+	"gopkg.in/yaml.v3"
 )
 
 // Version information - set by build process
@@ -32,6 +47,33 @@ type NativeMessagingRequest struct {
 	Tags   []string `json:"tags"`
 	Search string   `json:"search"`
 	Reveal bool     `json:"reveal,omitempty"` // Optional flag to reveal sensitive information
+	Stream bool     `json:"stream,omitempty"` // When Type is "search", opt into the streaming response framing below
+}
+
+// Streaming response framing. When a "search" request sets Stream: true,
+// processNativeMessagingRequest writes a sequence of framed messages instead
+// of a single response, so the Chrome extension can show progress while
+// vaults with many items are still being listed:
+//
+//   - {"type":"progress","stage":"listing","vault":"..."} once per vault, as
+//     onepassword.Client.ListWithProgress finishes searching it
+//   - {"type":"partial","sessions":[...]} with the sessions found in that
+//     vault, interleaved with the progress messages above
+//   - a terminal message identical to the non-streaming response: the
+//     matching session (single match), the session array (multiple
+//     matches), or {"type":"error","error":"..."}
+//
+// Clients that omit Stream, or set it false, keep receiving the original
+// single-response behaviour with no progress/partial frames.
+type nativeMessagingProgress struct {
+	Type  string `json:"type"`
+	Stage string `json:"stage"`
+	Vault string `json:"vault,omitempty"`
+}
+
+type nativeMessagingPartial struct {
+	Type     string                    `json:"type"`
+	Sessions []*core.CumulocitySession `json:"sessions"`
 }
 
 var rootCmd = &cobra.Command{
@@ -42,7 +84,8 @@ var rootCmd = &cobra.Command{
 This command provides smart filtering and selection of sessions:
 - Shows interactive picker for multiple sessions matching the filter
 - Automatically returns the session when filter matches exactly one item
-- Filter matches against session names, item names, URLs, and usernames
+- Filter matches against session names, item names, URLs, usernames,
+  tenants, and tags, ranked by relevance (see Filter mode below)
 - Support multiple urls per 1Password item showing one session per URL
 
 By default, sensitive information (passwords, TOTP secrets) is obfuscated in the output.
@@ -51,8 +94,92 @@ Use --reveal to show the actual values.
 Direct item access:
 - Use --item flag for direct item retrieval by ID or name
 - Use --uri flag for direct item retrieval using op://vault/item format
+  (or vault://mount/path for a HashiCorp Vault KV v2 secret)
 - Use --vault flag to limit searches to specific vault(s)
 
+Secret backends:
+- Use --backend to choose where sessions are listed from: "op" (1Password
+  CLI, the default), "connect" (1Password Connect Server REST API, via
+  OP_CONNECT_HOST/OP_CONNECT_TOKEN -- no op CLI or sign-in required, so it
+  works in containers/CI), "vault" (HashiCorp Vault KV v2), or a
+  comma-separated list of any of them to merge sessions from multiple
+  backends into one picker
+
+Authentication mode:
+- Use --auth to control basic vs. OIDC/OAuth2 login: "auto" (the default)
+  uses OIDC only for items configuring an oidc_issuer field, "basic" always
+  uses the item's username/password, "oidc" requires the item to configure
+  OIDC and fails otherwise
+- OIDC items provide oidc_issuer, oidc_client_id, and oidc_client_secret
+  fields (and optionally a stored refresh_token); a bearer token is
+  obtained by refreshing the stored refresh token or, failing that, an
+  authorization-code-with-PKCE flow via a loopback redirect, and the
+  renewed refresh token is written back to the item
+
+Tag filtering:
+- --tags accepts a label-selector expression (see pkg/core/selector):
+  "key" or "!key" test presence/absence, "key=value"/"key!=value" and
+  "key in (a,b)"/"key notin (a,b)" match against "key=value" or "key:value"
+  tags (value may use glob patterns like "prod-*"), commas AND terms
+  together, and "|" separates OR-ed groups, e.g. "env=prod,!deprecated"
+- A plain comma-separated list of bare tags (no operators) keeps this
+  tool's historical behavior of matching ANY of them, e.g. "c8y,production"
+
+Filter mode:
+- By default the [filter] argument is matched fuzzily: each session is
+  scored on how closely it matches (word-boundary and consecutive
+  characters count for more, gaps count for less), results are sorted by
+  relevance, and a session failing to match at all is dropped
+- Use --filter-mode=substring to fall back to a plain case-insensitive
+  substring match against session name, item name, and host, in their
+  original order, for scripts that depend on the exact legacy behavior
+
+Wildcard and regex hosts:
+- An item's URL may contain a single "*" (e.g. "https://xyz-*.example.com/")
+  to stand for a whole family of tenants; by default it's left as a literal
+  URL, but --expand-hosts turns each one into one session per concrete host,
+  resolved via an "_c8y._tcp" SRV lookup on the domain, falling back to a
+  sibling custom field labeled "hosts" (one hostname per line) if the
+  lookup finds nothing
+- A URL field labeled "url-regex" is always treated as a regular expression
+  matched against the "hosts" field's lines, regardless of --expand-hosts,
+  producing one session per matching line
+
+Session cache:
+- --cache-ttl caches a listing (keyed by the effective vaults and tags) as
+  JSON under $XDG_CACHE_HOME/c8y-session-1password, with passwords and TOTP
+  secrets encrypted at rest; a later invocation with a matching --vault/
+  --tags and a still-fresh entry skips "op" entirely
+- --cache-ttl=0 (the default) disables the cache; --no-cache bypasses it for
+  one invocation without changing the configured TTL
+- Run "c8y-session-1password cache refresh" (e.g. from cron) to repopulate
+  it ahead of time, so the interactive picker always starts instantly
+
+Timeouts and cancellation:
+- --timeout bounds every "op" invocation (0, the default, leaves them
+  unbounded); Ctrl-C cancels any "op" invocation in flight regardless of
+  --timeout
+
+Environment classification and naming rules:
+- Configure urlRules in config as a list of {match, environment, tags,
+  nameTemplate, priority}; the highest-priority regex (on the URL's href or
+  label) matching sets the session's Environment field, merges in its
+  tags, and can rename the session, with capture groups available as
+  "${1}" in environment and nameTemplate
+- Use --dry-run-rules to print which rule (if any) matched each session's
+  URL instead of selecting a session
+
+Custom rendering:
+- Use --template or --template-file to override the picker's session
+  description with a Go text/template (helpers: trimPrefix, trimSuffix,
+  default, env, normalizeURL, join, hostname, trimProto, title, lower); set
+  templates.title/description/name/uri/document in config for defaults, or
+  to override --output template's final document. templates.name and
+  templates.uri override the session Name and SessionURI a session is
+  built with (falling back to the built-in naming rules when unset).
+  Templates receive {{.Session}} (a CumulocitySession), plus {{.Item}}/
+  {{.URL}} where available.
+
 Native messaging mode:
 - Automatically activated when JSON is piped via stdin
 - Compatible with Chrome extension native messaging protocol
@@ -74,15 +201,21 @@ Environment Variables:
  * C8YOP_VAULT - Default vault to search in (can be vault name or ID)
  * C8YOP_TAGS - Default tags to filter by (comma-separated, defaults to "c8y" if not set)
  * C8YOP_ITEM - Default item to retrieve (item ID or name)
- * C8YOP_LOG_LEVEL - Logging level (debug, info, warn, error; defaults to warn)`,
+ * C8YOP_LOG_LEVEL - Logging level (debug, info, warn, error; defaults to warn)
+ * C8YOP_LOG_FORMAT - Logging output format (text, json; defaults to text)`,
 	Args:         cobra.MaximumNArgs(1),
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if there's input available on stdin (automatic detection)
+		socketPath, err := cmd.Flags().GetString("socket")
+		if err != nil {
+			return err
+		}
+
 		stat, err := os.Stdin.Stat()
 		if err == nil && (stat.Mode()&os.ModeCharDevice) == 0 {
 			// stdin has data (pipe or redirect), switch to native messaging mode
-			return runNativeMessaging()
+			return runNativeMessaging(socketPath)
 		}
 
 		vault, err := cmd.Flags().GetString("vault")
@@ -106,6 +239,31 @@ Environment Variables:
 			return err
 		}
 
+		filterMode, err := cmd.Flags().GetString("filter-mode")
+		if err != nil {
+			return err
+		}
+
+		expandHosts, err := cmd.Flags().GetBool("expand-hosts")
+		if err != nil {
+			return err
+		}
+
+		cacheTTL, err := cmd.Flags().GetDuration("cache-ttl")
+		if err != nil {
+			return err
+		}
+
+		noCache, err := cmd.Flags().GetBool("no-cache")
+		if err != nil {
+			return err
+		}
+
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+
 		reveal, err := cmd.Flags().GetBool("reveal")
 		if err != nil {
 			return err
@@ -126,6 +284,16 @@ Environment Variables:
 			return err
 		}
 
+		authMode, err := cmd.Flags().GetString("auth")
+		if err != nil {
+			return err
+		}
+
+		if err := loadTemplates(cmd); err != nil {
+			return err
+		}
+		loadURLRules()
+
 		// Combine no-color flags (either --no-color or --noColor disables colors)
 		noColorFinal := noColor || noColorCompat
 
@@ -144,9 +312,21 @@ Environment Variables:
 			item = getEnvWithFallback("C8YOP_ITEM", "CYOP_ITEM")
 		}
 
-		// Parse op:// URI if provided
+		// Parse the item URI, if provided. The scheme selects which backend
+		// resolves it: "op://vault/item" (the default, also assumed for a
+		// bare vault/item pair) or "vault://mount/path" for a HashiCorp
+		// Vault KV v2 secret.
+		itemScheme := "op"
 		if opURI != "" {
-			vaultFromURI, itemFromURI, err := onepassword.ParseOPURI(opURI)
+			if strings.HasPrefix(opURI, "vault://") {
+				itemScheme = "vault"
+			}
+
+			backend, err := resolveBackend(itemScheme, vault)
+			if err != nil {
+				return err
+			}
+			vaultFromURI, itemFromURI, err := backend.ResolveURI(opURI)
 			if err != nil {
 				return fmt.Errorf("invalid URI: %w", err)
 			}
@@ -164,8 +344,29 @@ Environment Variables:
 
 		// If we have a specific item, get it directly (vault is optional)
 		if item != "" {
+			if itemScheme != "op" {
+				backend, err := resolveBackend(itemScheme, vault)
+				if err != nil {
+					return err
+				}
+				backendItem, err := backend.GetItem(vault, item)
+				if err != nil {
+					return err
+				}
+				sessions := core.MapToSessionsScheme(backend.Scheme(), backendItem.Item, backendItem.Fields, backendItem.URLs, backendItem.VaultName, tags)
+				if len(sessions) == 0 {
+					return fmt.Errorf("no valid session found for item")
+				}
+				if err := populateOIDCToken(sessions[0], authMode); err != nil {
+					return err
+				}
+				return outputSession(sessions[0], reveal, outputFormat)
+			}
+
 			client := onepassword.NewClient(vault, tags...)
-			session, err := client.GetItem(vault, item)
+			client.HostResolver = hostResolverFor(expandHosts)
+			client.Timeout = timeout
+			session, err := client.GetItemContext(cmd.Context(), vault, item)
 			if err != nil {
 				return err
 			}
@@ -173,12 +374,53 @@ Environment Variables:
 			// Get TOTP if available
 			populateTOTP(session)
 
+			if err := populateOIDCToken(session, authMode); err != nil {
+				return err
+			}
+
 			return outputSession(session, reveal, outputFormat)
 		}
 
-		// Interactive/filtered selection mode
-		client := onepassword.NewClient(vault, tags...)
-		sessions, err := client.List()
+		// Interactive/filtered selection mode. When --socket is set, round-trip
+		// against a running 'serve' daemon instead of listing 1Password
+		// directly, so repeated invocations skip the per-call "op" signin
+		// check and vault listing.
+		backendFlag, err := cmd.Flags().GetString("backend")
+		if err != nil {
+			return err
+		}
+
+		var sessions []*core.CumulocitySession
+		if socketPath != "" {
+			sessions, err = querySocket(socketPath, NativeMessagingRequest{
+				Vaults: splitAndTrimString(vault),
+				Tags:   tags,
+				Reveal: reveal,
+			})
+		} else if backendFlag != "" && backendFlag != "op" {
+			// Fan out across every backend named in --backend (e.g.
+			// "op,vault") and merge their sessions before the picker's
+			// fuzzy filter, so a single picker can cover teams split
+			// between 1Password and Vault.
+			backends, backendErr := resolveBackends(backendFlag, vault)
+			if backendErr != nil {
+				return backendErr
+			}
+			var errs []error
+			sessions, errs = core.ListFromBackends(backends, vault, tags)
+			for _, listErr := range errs {
+				slog.Warn("backend listing failed", "error", listErr)
+			}
+			if len(sessions) == 0 && len(errs) > 0 {
+				err = errs[0]
+			}
+		} else {
+			client := onepassword.NewClient(vault, tags...)
+			client.HostResolver = hostResolverFor(expandHosts)
+			client.Cache, client.CacheTTL = cacheFor(cacheTTL, noCache)
+			client.Timeout = timeout
+			sessions, err = client.ListContext(cmd.Context())
+		}
 		if err != nil {
 			return err
 		}
@@ -187,10 +429,18 @@ Environment Variables:
 			return fmt.Errorf("no sessions found matching tags: %v", tags)
 		}
 
+		dryRunRules, err := cmd.Flags().GetBool("dry-run-rules")
+		if err != nil {
+			return err
+		}
+		if dryRunRules {
+			return printURLRuleMatches(sessions)
+		}
+
 		// Apply filter if provided
 		filteredSessions := sessions
 		if filter != "" {
-			filteredSessions = core.FilterSessions(sessions, filter)
+			filteredSessions = core.FilterSessions(sessions, filter, filterMode)
 		}
 
 		// Smart selection behavior
@@ -199,31 +449,80 @@ Environment Variables:
 		} else if len(filteredSessions) == 1 {
 			// Auto-select the single matching session
 			session := filteredSessions[0]
-			// Populate session details and TOTP from the full session list
-			populateSessionFromList(session, sessions)
+			// Populate session details, TOTP, and OIDC token from the full session list
+			if err := populateSessionFromList(session, sessions, authMode); err != nil {
+				return err
+			}
 			return outputSession(session, reveal, outputFormat)
+		} else if outputFormat == "yaml" || outputFormat == "json-list" {
+			// Preview mode: dump every match instead of launching the
+			// interactive picker, so callers can see what the picker would
+			// show without a TTY. "json-list" is plain JSON; "yaml" reuses
+			// the single-session YAML format's reveal-masking rules.
+			listFormat := outputFormat
+			if listFormat == "json-list" {
+				listFormat = "json"
+			}
+			return outputSessionList(filteredSessions, reveal, listFormat)
 		} else {
 			// Multiple sessions found, use interactive picker
 			vaultList := splitAndTrimString(vault)
 			metadata := picker.PickerMetadata{
-				Vaults:  vaultList,
-				Tags:    tags,
-				Filter:  filter,
-				NoColor: noColorFinal,
+				Vaults:     vaultList,
+				Tags:       tagsSelectorDisplay(tags),
+				Filter:     filter,
+				FilterMode: filterMode,
+				NoColor:    noColorFinal,
+			}
+			result, err := picker.PickResult(filteredSessions, metadata)
+			if errors.Is(err, picker.ErrNotInteractive) {
+				// Driven non-interactively (e.g. through --socket from a
+				// script or editor plugin): there's no terminal to render
+				// the picker against, so list every match instead.
+				return outputSessionList(filteredSessions, reveal, outputFormat)
 			}
-			session, err := picker.Pick(filteredSessions, metadata)
 			if err != nil {
 				return err
 			}
-			// Populate session details and TOTP from the full session list
-			populateSessionFromList(session, sessions)
+			session := result.Session
+			// Populate session details, TOTP, and OIDC token from the full session list
+			if err := populateSessionFromList(session, sessions, authMode); err != nil {
+				return err
+			}
+
+			// If the user toggled on additional sessions via the picker's
+			// multi-select bindings, export the whole batch alongside the
+			// single chosen session so callers can act on all of them.
+			if len(result.Selected) > 1 {
+				for _, s := range result.Selected {
+					if err := populateSessionFromList(s, sessions, authMode); err != nil {
+						return err
+					}
+				}
+				if err := outputBulkSelection(result.Selected, reveal); err != nil {
+					return err
+				}
+			}
+
+			if result.Action != "" && result.Action != picker.ActionUseSession {
+				return dispatchPickerAction(result.Action, session, reveal)
+			}
+
 			return outputSession(session, reveal, outputFormat)
 		}
 	},
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// Cancelling on SIGINT/SIGTERM propagates through cmd.Context() to any
+	// "op" invocation made via a *Context client method (see --timeout
+	// above and pkg/onepassword/client.go's withClientTimeout), so Ctrl-C
+	// kills the in-flight "op" process instead of leaving it running after
+	// this process exits.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -251,13 +550,22 @@ func setupLogging() {
 		level = slog.LevelWarn // Default to warning level
 	}
 
-	// Create a new logger with the specified level
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	}))
+	opts := &slog.HandlerOptions{Level: level}
+
+	// C8YOP_LOG_FORMAT selects the handler: "json" for log collectors that
+	// consume stderr from this binary as a Chrome native-messaging host or
+	// under a process supervisor, "text" (default) for a human reading a
+	// terminal.
+	var handler slog.Handler
+	switch strings.ToLower(os.Getenv("C8YOP_LOG_FORMAT")) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
 
 	// Set as the default logger
-	slog.SetDefault(logger)
+	slog.SetDefault(slog.New(handler))
 }
 
 func init() {
@@ -265,8 +573,18 @@ func init() {
 	rootCmd.PersistentFlags().String("vault", "", "Vault name or ID (optional - if not provided, use C8YOP_VAULT env var or use all vaults)")
 	rootCmd.PersistentFlags().String("item", "", "Specific item ID or name to retrieve (defaults to C8YOP_ITEM env var)")
 	rootCmd.PersistentFlags().String("uri", "", "Specific item with op://vault/item URI")
-	rootCmd.PersistentFlags().String("tags", "", "Comma-separated tags to filter by (defaults to C8YOP_TAGS env var, then 'c8y')")
-	rootCmd.PersistentFlags().StringP("output", "o", "json", "Output format (json, uri)")
+	rootCmd.PersistentFlags().String("tags", "", "Tags to filter by: a label selector (e.g. \"env=prod,!deprecated\" or \"env in (prod,staging)\"), or a plain comma list matching any tag (defaults to C8YOP_TAGS env var, then 'c8y')")
+	rootCmd.PersistentFlags().StringP("output", "o", "json", "Output format (json, yaml, uri, json-list, template). json-list and yaml on a multi-match filter preview the full list instead of launching the picker. template requires templates.document in config (--template/--template-file set only the description template)")
+	rootCmd.PersistentFlags().String("filter-mode", core.FilterModeFuzzy, "How the [filter] argument matches sessions: fuzzy (ranked relevance, the default) or substring (plain case-insensitive substring match)")
+	rootCmd.PersistentFlags().Bool("expand-hosts", false, "Expand a \"*\"-wildcard item URL into one session per concrete host (via an _c8y._tcp SRV lookup, falling back to a \"hosts\" custom field); a url-regex-labeled URL is always expanded against \"hosts\" regardless of this flag")
+	rootCmd.PersistentFlags().Duration("cache-ttl", 0, "Cache a vault/tag session listing on disk for this long before refetching from 1Password (0, the default, disables the cache); see the \"cache refresh\" command to repopulate it in the background")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Bound every \"op\" invocation to this duration (0, the default, leaves them unbounded); Ctrl-C also cancels any in-flight \"op\" invocation")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Never read or write the on-disk session cache, even if --cache-ttl is set")
+	rootCmd.PersistentFlags().String("backend", "op", "Secret backend(s) to list sessions from, comma-separated (op, connect, vault)")
+	rootCmd.PersistentFlags().String("auth", "auto", "Authentication mode: basic, oidc, or auto (use OIDC only when the item configures oidc_issuer)")
+	rootCmd.PersistentFlags().String("template", "", "Inline Go template overriding the picker's session description (see templates.description in config for Title/Document too)")
+	rootCmd.PersistentFlags().String("template-file", "", "Path to a file containing the Go template from --template (takes precedence over --template)")
+	rootCmd.PersistentFlags().Bool("dry-run-rules", false, "Print which urlRules config entry (if any) matched each session's URL, instead of selecting a session")
 	rootCmd.PersistentFlags().Bool("reveal", false, "Show sensitive information like passwords and TOTP secrets in output")
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output in picker")
 	rootCmd.PersistentFlags().Bool("noColor", false, "Disable colored output in picker (go-c8y-cli compatibility)")
@@ -347,6 +665,34 @@ var debugColorsCmd = &cobra.Command{
 	},
 }
 
+// hostResolverFor returns the onepassword.HostResolver --expand-hosts
+// selects: a DNSResolver when the flag is set, or nil (NoopResolver's
+// literal-URL-per-session behavior) when it isn't.
+func hostResolverFor(expandHosts bool) onepassword.HostResolver {
+	if !expandHosts {
+		return nil
+	}
+	return onepassword.DNSResolver{}
+}
+
+// cacheFor returns the on-disk session cache --cache-ttl/--no-cache select
+// for a Client: nil/0 (caching disabled) when ttl isn't positive, --no-cache
+// is set, or the cache directory can't be opened (logged and treated as a
+// soft failure, not a command error), otherwise an opened *cache.Store
+// paired with ttl.
+func cacheFor(ttl time.Duration, noCache bool) (*cache.Store, time.Duration) {
+	if ttl <= 0 || noCache {
+		return nil, 0
+	}
+
+	store, err := cache.Open()
+	if err != nil {
+		slog.Warn("Failed to open session cache, continuing without it", "error", err)
+		return nil, 0
+	}
+	return store, ttl
+}
+
 // Helper function to populate TOTP for a session
 func populateTOTP(session *core.CumulocitySession) {
 	if session.TOTPSecret != "" {
@@ -358,14 +704,185 @@ func populateTOTP(session *core.CumulocitySession) {
 }
 
 // Helper function to find and populate session details from list
-func populateSessionFromList(targetSession *core.CumulocitySession, allSessions []*core.CumulocitySession) {
+func populateSessionFromList(targetSession *core.CumulocitySession, allSessions []*core.CumulocitySession, authMode string) error {
 	for _, s := range allSessions {
 		if targetSession.ItemID == s.ItemID {
 			targetSession.Password = s.Password
 			populateTOTP(targetSession)
-			break
+			return populateOIDCToken(targetSession, authMode)
+		}
+	}
+	return nil
+}
+
+// populateOIDCToken obtains a bearer token for session when its item
+// configures OIDC (an oidc_issuer field), honoring authMode: "basic" always
+// skips, "oidc" requires the item to configure OIDC, and "auto" (the
+// default) uses OIDC only when the item configures it. A renewed refresh
+// token is written back to the 1Password item so later invocations can
+// skip the interactive authorization flow.
+func populateOIDCToken(session *core.CumulocitySession, authMode string) error {
+	if authMode == "basic" {
+		return nil
+	}
+	if session.OIDCIssuer == "" {
+		if authMode == "oidc" {
+			return fmt.Errorf("item %q does not configure OIDC (missing oidc_issuer field)", session.ItemName)
+		}
+		return nil
+	}
+
+	result, err := oidc.Obtain(context.Background(), oidc.Config{
+		Issuer:       session.OIDCIssuer,
+		ClientID:     session.OIDCClientID,
+		ClientSecret: session.OIDCClientSecret,
+		RefreshToken: session.RefreshToken,
+	})
+	if err != nil {
+		return fmt.Errorf("obtaining OIDC token for %q: %w", session.ItemName, err)
+	}
+
+	session.AccessToken = result.AccessToken
+	session.TokenType = result.TokenType
+
+	if result.RefreshToken != "" && result.RefreshToken != session.RefreshToken && strings.HasPrefix(session.SessionURI, "op://") {
+		if err := onepassword.NewClient(session.VaultID).UpdateRefreshToken(session.VaultID, session.ItemID, result.RefreshToken); err != nil {
+			slog.Warn("Failed to store renewed OIDC refresh token", "item", session.ItemName, "error", err)
+		} else {
+			session.RefreshToken = result.RefreshToken
+		}
+	}
+
+	return nil
+}
+
+// resolveBackend returns the core.SecretBackend matching scheme ("op",
+// "vault", or "connect"). vaultMount is used as the Vault KV v2 mount when
+// no mount is otherwise specified (the vault backend reuses --vault for
+// this, the same way "op" reuses it as a vault name).
+func resolveBackend(scheme, vaultMount string) (core.SecretBackend, error) {
+	switch scheme {
+	case "", "op":
+		return onepassword.NewBackend(), nil
+	case "connect":
+		return onepassword.NewConnectBackendFromEnv()
+	case "vault":
+		vaultClient, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("creating vault client: %w", err)
+		}
+		return vaultsecrets.NewBackend(vaultClient, vaultMount), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend: %s (supported: op, connect, vault)", scheme)
+	}
+}
+
+// resolveBackends parses a comma-separated --backend value (e.g. "op,vault")
+// into the SecretBackend instances session listing should fan out across.
+func resolveBackends(backendFlag, vaultMount string) ([]core.SecretBackend, error) {
+	schemes := splitAndTrimString(backendFlag)
+	if len(schemes) == 0 {
+		schemes = []string{"op"}
+	}
+
+	backends := make([]core.SecretBackend, 0, len(schemes))
+	for _, scheme := range schemes {
+		backend, err := resolveBackend(scheme, vaultMount)
+		if err != nil {
+			return nil, err
 		}
+		backends = append(backends, backend)
 	}
+	return backends, nil
+}
+
+// loadTemplates parses --template/--template-file (overriding the
+// description template) and the config's templates: block (providing
+// title/description/name/uri/document defaults, and the only way to set
+// title, name, uri, or document), then installs them for
+// CumulocitySession.Title()/Description(), the Name/SessionURI
+// MapToSessions assigns, and "--output template" to use.
+func loadTemplates(cmd *cobra.Command) error {
+	inline, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return err
+	}
+	file, err := cmd.Flags().GetString("template-file")
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Load()
+
+	descriptionSource := cfg.Templates.Description
+	switch {
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading --template-file: %w", err)
+		}
+		descriptionSource = string(data)
+	case inline != "":
+		descriptionSource = inline
+	}
+
+	var titleTmpl, descriptionTmpl, nameTmpl, uriTmpl, documentTmpl *texttemplate.Template
+	if cfg.Templates.Title != "" {
+		if titleTmpl, err = core.ParseTemplate("title", cfg.Templates.Title); err != nil {
+			return fmt.Errorf("parsing templates.title: %w", err)
+		}
+	}
+	if descriptionSource != "" {
+		if descriptionTmpl, err = core.ParseTemplate("description", descriptionSource); err != nil {
+			return fmt.Errorf("parsing description template: %w", err)
+		}
+	}
+	if cfg.Templates.Name != "" {
+		if nameTmpl, err = core.ParseTemplate("name", cfg.Templates.Name); err != nil {
+			return fmt.Errorf("parsing templates.name: %w", err)
+		}
+	}
+	if cfg.Templates.URI != "" {
+		if uriTmpl, err = core.ParseTemplate("uri", cfg.Templates.URI); err != nil {
+			return fmt.Errorf("parsing templates.uri: %w", err)
+		}
+	}
+	if cfg.Templates.Document != "" {
+		if documentTmpl, err = core.ParseTemplate("document", cfg.Templates.Document); err != nil {
+			return fmt.Errorf("parsing templates.document: %w", err)
+		}
+	}
+
+	core.SetSessionTemplates(titleTmpl, descriptionTmpl)
+	core.SetNameTemplate(nameTmpl)
+	core.SetURITemplate(uriTmpl)
+	core.SetDocumentTemplate(documentTmpl)
+	return nil
+}
+
+// loadURLRules installs the config's urlRules as the active URL rule
+// engine (see core.SetURLRuleEngine), so every session built afterwards is
+// classified by them. An empty or absent urlRules config is equivalent to
+// not setting an engine at all -- Match simply never fires.
+func loadURLRules() {
+	core.SetURLRuleEngine(config.NewURLRuleEngine(config.Load().URLRules))
+}
+
+// printURLRuleMatches implements --dry-run-rules: for each session, report
+// which configured urlRules entry (if any) matched its Host, and what it
+// resolved to, without selecting or outputting a session.
+func printURLRuleMatches(sessions []*core.CumulocitySession) error {
+	engine := config.NewURLRuleEngine(config.Load().URLRules)
+	for _, session := range sessions {
+		rule, expanded, matched := engine.MatchVerbose(session.Host, "")
+		if !matched {
+			fmt.Printf("%s: no rule matched\n", session.Host)
+			continue
+		}
+		fmt.Printf("%s: matched %q (environment=%q, tags=%v, name=%q)\n",
+			session.Host, rule.Match, expanded.Environment, rule.Tags, expanded.NameTemplate)
+	}
+	return nil
 }
 
 // Helper function to get environment variable with fallback compatibility
@@ -416,37 +933,102 @@ func parseTags(flagValue string) []string {
 	return tags
 }
 
+// tagsSelectorDisplay reassembles tags into the picker's query summary form.
+// parseTags splits --tags on every top-level comma, which also re-splits a
+// selector expression's own AND separator (e.g. "env in (a,b)" becomes
+// ["env in (a", "b)"]); re-joining and re-parsing recovers the original
+// expression so the summary shows it correctly instead of the split pieces.
+func tagsSelectorDisplay(tags []string) []string {
+	sel, err := selector.Parse(strings.Join(tags, ","))
+	if err != nil || sel.String() == "" {
+		return tags
+	}
+	return []string{sel.String()}
+}
+
 // Helper function to output session in the specified format
 func outputSession(session *core.CumulocitySession, reveal bool, outputFormat string) error {
 	switch outputFormat {
-	case "json":
+	case "json", "json-list":
 		return outputSessionAsJSON(session, reveal)
+	case "yaml":
+		return outputSessionAsYAML(session, reveal)
 	case "uri":
 		return outputSessionAsURI(session)
+	case "template":
+		return outputSessionAsTemplate(session, reveal)
 	default:
-		return fmt.Errorf("unsupported output format: %s (supported: json, uri)", outputFormat)
+		return fmt.Errorf("unsupported output format: %s (supported: json, yaml, uri, json-list, template)", outputFormat)
+	}
+}
+
+// outputSessionAsTemplate renders session with the document template set
+// from the config's templates.document value (see loadTemplates), applying the same reveal-masking rules as the other
+// output formats first.
+func outputSessionAsTemplate(session *core.CumulocitySession, reveal bool) error {
+	if core.DocumentTemplate() == nil {
+		return fmt.Errorf("--output template requires a document template (set templates.document in config)")
+	}
+	out, err := core.RenderDocument(applyRevealFlag(session, reveal))
+	if err != nil {
+		return err
 	}
+	fmt.Printf("%s\n", out)
+	return nil
 }
 
 // Helper function to output session as JSON
 func outputSessionAsJSON(session *core.CumulocitySession, reveal bool) error {
-	// Create a copy of the session to avoid modifying the original
-	outputSession := *session
+	out, err := json.MarshalIndent(applyRevealFlag(session, reveal), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", out)
+	return nil
+}
 
-	// Obfuscate sensitive fields if reveal is false
-	if !reveal {
-		if outputSession.Password != "" {
-			outputSession.Password = "***"
-		}
-		if outputSession.TOTP != "" {
-			outputSession.TOTP = "***"
+// Helper function to output session as YAML, using the same reveal-masking
+// rules as outputSessionAsJSON.
+func outputSessionAsYAML(session *core.CumulocitySession, reveal bool) error {
+	out, err := yaml.Marshal(applyRevealFlag(session, reveal))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s", out)
+	return nil
+}
+
+// outputSessionList prints every candidate session on stdout as JSON, YAML,
+// or op:// URIs, depending on outputFormat ("json-list" is accepted as a
+// synonym for "json" here). Used in place of the interactive picker when
+// there is more than one match but no terminal to render it against, and by
+// the yaml and json-list output formats for previewing what the picker would
+// show on a multi-match filter.
+func outputSessionList(sessions []*core.CumulocitySession, reveal bool, outputFormat string) error {
+	if outputFormat == "uri" {
+		for _, session := range sessions {
+			if err := outputSessionAsURI(session); err != nil {
+				return err
+			}
 		}
-		if outputSession.TOTPSecret != "" {
-			outputSession.TOTPSecret = "***"
+		return nil
+	}
+
+	outputSessions := make([]*core.CumulocitySession, 0, len(sessions))
+	for _, session := range sessions {
+		outputSessions = append(outputSessions, applyRevealFlag(session, reveal))
+	}
+
+	if outputFormat == "yaml" {
+		out, err := yaml.Marshal(outputSessions)
+		if err != nil {
+			return err
 		}
+		fmt.Printf("%s", out)
+		return nil
 	}
 
-	out, err := json.MarshalIndent(&outputSession, "", "  ")
+	out, err := json.MarshalIndent(outputSessions, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -454,6 +1036,91 @@ func outputSessionAsJSON(session *core.CumulocitySession, reveal bool) error {
 	return nil
 }
 
+// dispatchPickerAction carries out the action chosen from the picker's
+// per-item action panel (everything except "Use session", which falls
+// through to the normal outputSession path).
+func dispatchPickerAction(action string, session *core.CumulocitySession, reveal bool) error {
+	switch action {
+	case picker.ActionCopyBaseURL:
+		return copyToClipboard(session.Host)
+	case picker.ActionCopyUsername:
+		return copyToClipboard(session.Username)
+	case picker.ActionOpenBrowser:
+		return openInBrowser(session.Host)
+	case picker.ActionReveal:
+		cmd := exec.Command("op", "item", "view", session.ItemID, "--reveal")
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	case picker.ActionEditTags:
+		cmd := exec.Command("op", "item", "edit", session.ItemID)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	default:
+		return fmt.Errorf("unknown picker action: %s", action)
+	}
+}
+
+// copyToClipboard pipes a value into the platform clipboard utility. Falls
+// back to printing the value if no clipboard tool is available.
+func copyToClipboard(value string) error {
+	for _, tool := range []string{"pbcopy", "wl-copy", "xclip", "xsel"} {
+		path, err := exec.LookPath(tool)
+		if err != nil {
+			continue
+		}
+		args := []string{}
+		if tool == "xclip" {
+			args = []string{"-selection", "clipboard"}
+		} else if tool == "xsel" {
+			args = []string{"--clipboard", "--input"}
+		}
+		cmd := exec.Command(path, args...)
+		cmd.Stdin = strings.NewReader(value)
+		return cmd.Run()
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+// openInBrowser opens a URL using the platform's default handler.
+func openInBrowser(url string) error {
+	if url == "" {
+		return fmt.Errorf("session has no host URL to open")
+	}
+
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Run()
+}
+
+// Helper function to print the bulk-selected sessions (picker multi-select)
+// to stderr as JSON so callers can script bulk delete/export/open actions
+// without interfering with the single-session output on stdout.
+func outputBulkSelection(sessions []*core.CumulocitySession, reveal bool) error {
+	outputSessions := make([]*core.CumulocitySession, 0, len(sessions))
+	for _, session := range sessions {
+		outputSessions = append(outputSessions, applyRevealFlag(session, reveal))
+	}
+
+	out, err := json.MarshalIndent(outputSessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Selected %d session(s):\n%s\n", len(outputSessions), out)
+	return nil
+}
+
 // Helper function to output session as op:// URI
 func outputSessionAsURI(session *core.CumulocitySession) error {
 	if session.VaultID == "" || session.ItemID == "" {
@@ -473,12 +1140,18 @@ func outputSessionAsURI(session *core.CumulocitySession) error {
 }
 
 // Helper function to run the command in native messaging mode
-func runNativeMessaging() error {
+func runNativeMessaging(socketPath string) error {
 	slog.Debug("Starting native messaging mode")
 
+	// requestID identifies each message on this persistent connection, so
+	// operators can correlate which Chrome tab's request produced which op
+	// call when multiple tabs multiplex through one message loop.
+	var requestID int
+
 	// Chrome Native Messaging protocol: persistent connection with message loop
 	for {
-		slog.Debug("Waiting for next message from Chrome extension")
+		requestID++
+		slog.Debug("Waiting for next message from Chrome extension", "request_id", requestID)
 
 		// Step 1: Read 4-byte length prefix
 		lengthBytes := make([]byte, 4)
@@ -534,7 +1207,7 @@ func runNativeMessaging() error {
 		slog.Debug("Parsed native messaging request", "request", req)
 
 		// Step 5: Process the message and send response
-		err = processNativeMessagingRequest(req)
+		err = processNativeMessagingRequest(req, socketPath, requestID)
 		if err != nil {
 			slog.Debug("Failed to process request", "error", err)
 			// Send error response and continue listening
@@ -553,10 +1226,10 @@ func runNativeMessaging() error {
 }
 
 // Helper function to process a single native messaging request
-func processNativeMessagingRequest(req NativeMessagingRequest) error {
+func processNativeMessagingRequest(req NativeMessagingRequest, socketPath string, requestID int) error {
 	// Handle special request types
 	if req.Type == "test_auth" {
-		slog.Debug("Handling test_auth request")
+		slog.Debug("Handling test_auth request", "request_id", requestID)
 		return handleAuthTest(true) // Always use native messaging format in native messaging mode
 	}
 
@@ -592,7 +1265,7 @@ func processNativeMessagingRequest(req NativeMessagingRequest) error {
 	filter = req.Search
 
 	// Log the effective vaults, tags, and filter
-	slog.Debug("Effective vaults, tags, and filter", "vaults", vaults, "tags", tags, "filter", filter)
+	slog.Debug("Effective vaults, tags, and filter", "request_id", requestID, "vaults", vaults, "tags", tags, "filter", filter)
 
 	// Convert vaults slice to comma-separated string for NewClient
 	var vaultString string
@@ -600,9 +1273,40 @@ func processNativeMessagingRequest(req NativeMessagingRequest) error {
 		vaultString = strings.Join(vaults, ",")
 	}
 
-	// Use the existing logic to process the request
-	client := onepassword.NewClient(vaultString, tags...)
-	sessions, err := client.List()
+	// Use the existing logic to process the request. When --socket is set,
+	// round-trip against a running 'serve' daemon instead of listing
+	// 1Password directly.
+	var sessions []*core.CumulocitySession
+	var err error
+	streaming := req.Type == "search" && req.Stream && socketPath == ""
+	// Native messaging requests have no --expand-hosts/--cache-ttl flags,
+	// so clients built here keep the default NoopResolver (literal URLs,
+	// unexpanded) and caching disabled.
+	if socketPath != "" {
+		sessions, err = querySocket(socketPath, req)
+	} else if streaming {
+		sessions, err = onepassword.NewClient(vaultString, tags...).ListWithProgress(
+			func(vaultName string, vaultSessions []*core.CumulocitySession) {
+				if sendErr := sendNativeMessagingResponse(nativeMessagingProgress{Type: "progress", Stage: "listing", Vault: vaultName}); sendErr != nil {
+					slog.Debug("Failed to send progress message", "request_id", requestID, "error", sendErr)
+					return
+				}
+				if len(vaultSessions) == 0 {
+					return
+				}
+				revealedSessions := make([]*core.CumulocitySession, len(vaultSessions))
+				for i, s := range vaultSessions {
+					revealedSessions[i] = applyRevealFlag(s, req.Reveal)
+				}
+				if sendErr := sendNativeMessagingResponse(nativeMessagingPartial{Type: "partial", Sessions: revealedSessions}); sendErr != nil {
+					slog.Debug("Failed to send partial message", "request_id", requestID, "error", sendErr)
+				}
+			},
+		)
+	} else {
+		client := onepassword.NewClient(vaultString, tags...)
+		sessions, err = client.List()
+	}
 	if err != nil {
 		return err
 	}
@@ -611,10 +1315,11 @@ func processNativeMessagingRequest(req NativeMessagingRequest) error {
 		return fmt.Errorf("no sessions found matching vaults: %v and tags: %v", vaults, tags)
 	}
 
-	// Apply filter if provided
+	// Apply filter if provided. Native messaging requests have no
+	// --filter-mode flag, so they always get ranked fuzzy matching.
 	filteredSessions := sessions
 	if filter != "" {
-		filteredSessions = core.FilterSessions(sessions, filter)
+		filteredSessions = core.FilterSessions(sessions, filter, core.FilterModeFuzzy)
 	}
 
 	// Smart selection behavior
@@ -623,15 +1328,20 @@ func processNativeMessagingRequest(req NativeMessagingRequest) error {
 	} else if len(filteredSessions) == 1 {
 		// Auto-select the single matching session
 		session := filteredSessions[0]
-		// Populate session details and TOTP from the full session list
-		populateSessionFromList(session, sessions)
+		// Populate session details and TOTP from the full session list. Native
+		// messaging requests have no --auth flag, so OIDC items use "auto".
+		if err := populateSessionFromList(session, sessions, "auto"); err != nil {
+			return err
+		}
 		return outputSessionNativeMessaging(session, req.Reveal, true) // Use reveal flag from request
 	} else {
 		// Multiple sessions found, return as JSON array
 		var outputSessions []*core.CumulocitySession
 		for _, session := range filteredSessions {
 			// Populate session details and TOTP from the full session list
-			populateSessionFromList(session, sessions)
+			if err := populateSessionFromList(session, sessions, "auto"); err != nil {
+				return err
+			}
 			outputSessions = append(outputSessions, session)
 		}
 		return outputSessionsNativeMessaging(outputSessions, req.Reveal, true) // Use reveal flag from request
@@ -663,6 +1373,15 @@ func applyRevealFlag(session *core.CumulocitySession, reveal bool) *core.Cumuloc
 		if outputSession.TOTPSecret != "" {
 			outputSession.TOTPSecret = "***"
 		}
+		if outputSession.AccessToken != "" {
+			outputSession.AccessToken = "***"
+		}
+		if outputSession.OIDCClientSecret != "" {
+			outputSession.OIDCClientSecret = "***"
+		}
+		if outputSession.RefreshToken != "" {
+			outputSession.RefreshToken = "***"
+		}
 	}
 
 	return &outputSession
@@ -702,8 +1421,16 @@ func outputSessionsNativeMessaging(sessions []*core.CumulocitySession, reveal bo
 	return outputJSONNativeMessaging(outputSessions, isNativeMessagingFormat)
 }
 
+// nativeMessageMu serializes writeNativeMessage so that a streaming
+// request's progress/partial frames, written from the ListWithProgress
+// callback, never interleave with each other or with the terminal frame.
+var nativeMessageMu sync.Mutex
+
 // Helper function to write native messaging format
 func writeNativeMessage(jsonData []byte) error {
+	nativeMessageMu.Lock()
+	defer nativeMessageMu.Unlock()
+
 	// Write 4-byte little-endian length prefix
 	length := uint32(len(jsonData))
 	lengthBytes := make([]byte, 4)
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// saveCmd writes a session back to 1Password, turning the tool from
+// read-only into a two-way sync so tenants can be provisioned from
+// scripts instead of clicked together in the 1Password app.
+var saveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Create or update a 1Password item from a Cumulocity session",
+	Long: `Reads a Cumulocity session (as core.CumulocitySession JSON) from
+--file, or stdin if --file is omitted, and creates or updates the
+matching 1Password Login item: username, password, the tenant custom
+field, the TOTP field, and the session's URL.
+
+If the session's sessionUri field (or --uri) is already an
+"op://vault/item" URI, that item is updated; otherwise a new item is
+created in --vault, titled from the session's itemName or name.
+
+Use --dry-run to print the "op item create/edit" invocation instead of
+running it, and --redact to mask the password and TOTP secret in that
+preview.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+		uri, err := cmd.Flags().GetString("uri")
+		if err != nil {
+			return err
+		}
+		vault, err := cmd.Flags().GetString("vault")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		redact, err := cmd.Flags().GetBool("redact")
+		if err != nil {
+			return err
+		}
+
+		var input io.Reader = os.Stdin
+		if file != "" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("opening %s: %w", file, err)
+			}
+			defer f.Close()
+			input = f
+		}
+
+		var session core.CumulocitySession
+		if err := json.NewDecoder(input).Decode(&session); err != nil {
+			return fmt.Errorf("decoding session: %w", err)
+		}
+
+		if uri != "" {
+			session.SessionURI = uri
+		}
+		if vault == "" {
+			vault = getEnvWithFallback("C8YOP_VAULT", "CYOP_VAULT")
+		}
+
+		client := onepassword.NewClient(vault)
+		if err := client.SaveSession(&session, onepassword.SaveOptions{DryRun: dryRun, Redact: redact}); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			fmt.Println("Saved session to 1Password")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(saveCmd)
+	saveCmd.Flags().String("file", "", "Read the session from this file instead of stdin")
+	saveCmd.Flags().String("uri", "", "Update this existing op://vault/item instead of creating a new item")
+	saveCmd.Flags().String("vault", "", "Vault to create a new item in (defaults to C8YOP_VAULT or CYOP_VAULT env var)")
+	saveCmd.Flags().Bool("dry-run", false, "Print the op item create/edit invocation instead of running it")
+	saveCmd.Flags().Bool("redact", false, "Mask the password and TOTP secret in --dry-run output")
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// DaemonResponse is the line-delimited JSON-RPC response written back over
+// the serve socket, one per request line.
+type DaemonResponse struct {
+	Sessions []*core.CumulocitySession `json:"sessions,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a background daemon that caches 1Password sessions over a Unix socket",
+	Long: `Starts a long-lived daemon that lists sessions at most once per TTL window
+and serves them to CLI invocations over a Unix socket, using the same
+request shape as native messaging mode (line-delimited JSON instead of
+length-prefixed frames). Point a client invocation at it with
+--socket <path> to skip the per-invocation "op" signin check and vault
+listing.
+
+On Windows, named pipes are not yet implemented and this command returns
+an error.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socketPath, err := cmd.Flags().GetString("socket")
+		if err != nil {
+			return err
+		}
+		if socketPath == "" {
+			socketPath = defaultSocketPath()
+		}
+
+		ttl, err := cmd.Flags().GetDuration("ttl")
+		if err != nil {
+			return err
+		}
+
+		return runDaemon(socketPath, ttl)
+	},
+}
+
+func init() {
+	serveCmd.Flags().Duration("ttl", 30*time.Second, "How long to cache a vault/tag session listing before refreshing")
+	rootCmd.AddCommand(serveCmd)
+
+	rootCmd.PersistentFlags().String("socket", "", "Unix socket path of a running 'serve' daemon to query instead of calling 1Password directly")
+}
+
+// defaultSocketPath returns the socket path used when --socket is not given
+// an explicit value, preferring XDG_RUNTIME_DIR (cleared on logout/reboot)
+// over a fixed spot in the system temp directory.
+func defaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "c8y-session-1password.sock")
+	}
+	return filepath.Join(os.TempDir(), "c8y-session-1password.sock")
+}
+
+// cacheEntry holds a TTL-bounded, secret-redacted session listing for one
+// vault/tag combination.
+type cacheEntry struct {
+	sessions []*core.CumulocitySession
+	expires  time.Time
+}
+
+// daemon serves session listings over the serve socket, caching redacted
+// results per vault/tag combination so repeated invocations don't each pay
+// for a fresh "op" signin check and vault listing.
+type daemon struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	cache map[string]*cacheEntry
+}
+
+func runDaemon(socketPath string, ttl time.Duration) error {
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("serve: named pipes are not yet supported on windows")
+	}
+
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale socket %s: %w", socketPath, err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	defer listener.Close()
+
+	// Unix sockets inherit the umask like any other file, so on a
+	// multi-user box another local user could otherwise connect and pull
+	// out unredacted sessions via Reveal. Match pkg/cache's 0o600 for its
+	// on-disk secrets.
+	if err := os.Chmod(socketPath, 0o600); err != nil {
+		return fmt.Errorf("restricting permissions on %s: %w", socketPath, err)
+	}
+
+	slog.Info("daemon listening", "socket", socketPath, "ttl", ttl)
+
+	d := &daemon{ttl: ttl, cache: make(map[string]*cacheEntry)}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("accept: %w", err)
+		}
+		go d.handleConn(conn)
+	}
+}
+
+// handleConn reads newline-delimited JSON requests from conn and writes a
+// newline-delimited JSON DaemonResponse for each, until the client
+// disconnects.
+func (d *daemon) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var req NativeMessagingRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			_ = encoder.Encode(DaemonResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		if err := encoder.Encode(d.handle(req)); err != nil {
+			slog.Debug("daemon: failed to write response", "error", err)
+			return
+		}
+	}
+}
+
+func (d *daemon) handle(req NativeMessagingRequest) DaemonResponse {
+	if req.Type == "test_auth" {
+		if err := handleAuthTest(false); err != nil {
+			return DaemonResponse{Error: err.Error()}
+		}
+		return DaemonResponse{}
+	}
+
+	vaultString := strings.Join(req.Vaults, ",")
+	tags := req.Tags
+	if len(tags) == 0 {
+		tags = []string{"c8y"}
+	}
+
+	sessions, err := d.sessions(vaultString, tags, req.Reveal)
+	if err != nil {
+		return DaemonResponse{Error: err.Error()}
+	}
+
+	if req.Search != "" {
+		sessions = core.FilterSessions(sessions, req.Search, core.FilterModeFuzzy)
+	}
+
+	return DaemonResponse{Sessions: sessions}
+}
+
+// sessions returns the session listing for vault/tags, refreshing the cache
+// once it expires. Revealed requests always bypass the cache and fetch
+// live, and the plaintext secrets they return are never written back into
+// it -- only a redacted copy is cached, so a revealed secret never outlives
+// the single request that asked for it.
+func (d *daemon) sessions(vault string, tags []string, reveal bool) ([]*core.CumulocitySession, error) {
+	key := cacheKey(vault, tags)
+
+	if !reveal {
+		if cached, ok := d.cached(key); ok {
+			return cached, nil
+		}
+	}
+
+	sessions, err := d.list(vault, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := redactSecrets(sessions)
+	d.mu.Lock()
+	d.cache[key] = &cacheEntry{sessions: redacted, expires: time.Now().Add(d.ttl)}
+	d.mu.Unlock()
+
+	if reveal {
+		return sessions, nil
+	}
+	return redacted, nil
+}
+
+func (d *daemon) cached(key string) ([]*core.CumulocitySession, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.sessions, true
+}
+
+// list fetches sessions from 1Password, retrying once via handleAuthTest if
+// the first attempt fails (e.g. because the user ran "op signout" since the
+// daemon started).
+func (d *daemon) list(vault string, tags []string) ([]*core.CumulocitySession, error) {
+	sessions, err := onepassword.NewClient(vault, tags...).List()
+	if err == nil {
+		return sessions, nil
+	}
+
+	slog.Warn("daemon: list failed, attempting op signin", "error", err)
+	if authErr := handleAuthTest(false); authErr != nil {
+		return nil, fmt.Errorf("%w (re-auth also failed: %v)", err, authErr)
+	}
+
+	return onepassword.NewClient(vault, tags...).List()
+}
+
+func cacheKey(vault string, tags []string) string {
+	return vault + "|" + strings.Join(tags, ",")
+}
+
+// redactSecrets returns copies of sessions with every secret field cleared,
+// safe to hold in the cache past the lifetime of a single request.
+func redactSecrets(sessions []*core.CumulocitySession) []*core.CumulocitySession {
+	out := make([]*core.CumulocitySession, len(sessions))
+	for i, s := range sessions {
+		redacted := *s
+		redacted.Password = ""
+		redacted.TOTP = ""
+		redacted.TOTPSecret = ""
+		out[i] = &redacted
+	}
+	return out
+}
+
+// querySocket sends req to the daemon listening on socketPath and returns
+// the sessions from its response.
+func querySocket(socketPath string, req NativeMessagingRequest) ([]*core.CumulocitySession, error) {
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("--socket: named pipes are not yet supported on windows")
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to daemon at %s: %w", socketPath, err)
+	}
+	defer conn.Close()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("writing request to daemon: %w", err)
+	}
+
+	var resp DaemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	return resp.Sessions, nil
+}
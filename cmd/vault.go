@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/hashicorp/vault/sdk/plugin"
+	"github.com/spf13/cobra"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/vaultbackend"
+)
+
+var vaultCmd = &cobra.Command{
+	Use:    "vault",
+	Short:  "HashiCorp Vault secrets-engine plugin mode",
+	Hidden: true,
+}
+
+var vaultServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Run as a HashiCorp Vault secrets-engine plugin",
+	Long: `Serves this binary as a HashiCorp Vault secrets-engine plugin over
+the Vault plugin gRPC protocol, so a Vault cluster can read Cumulocity
+sessions the same way it reads any other secret.
+
+Register the built binary with Vault and enable it as a secrets engine:
+
+  vault plugin register -sha256=<sha256> secret c8y-session-1password
+  vault secrets enable -path=c8y c8y-session-1password
+
+Then configure it and read sessions like any other Vault secret:
+
+  vault write c8y/config default_vault=Employee tags=c8y auth_mode=service_account service_account_token=...
+  vault list c8y/sessions
+  vault read c8y/sessions/<name>
+  vault read c8y/sessions/Employee/my-item
+  vault read c8y/uri/op:/Employee/my-item
+
+This subcommand is invoked by the Vault server itself (via the plugin's
+mlock/handshake protocol) and is not meant to be run directly.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return plugin.Serve(&plugin.ServeOpts{
+			BackendFactoryFunc: vaultbackend.Factory,
+		})
+	},
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultServerCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
@@ -0,0 +1,176 @@
+// Package cache provides a TTL-bounded, at-rest-encrypted local cache of
+// resolved session listings under $XDG_CACHE_HOME/c8y-session-1password,
+// so the interactive picker can start instantly instead of re-shelling out
+// to "op" on every invocation. See onepassword.Client's Cache/CacheTTL
+// fields for how it's consulted, and the "cache refresh" command for how
+// it's repopulated in the background.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// Key identifies one cached listing: a vault selector plus a tag
+// selector, the two inputs Client.List resolves sessions from.
+type Key string
+
+// BuildKey derives a stable Key from vaults and tags, independent of the
+// order either was supplied in.
+func BuildKey(vaults, tags []string) Key {
+	raw := strings.Join(sortedCopy(vaults), ",") + "|" + strings.Join(sortedCopy(tags), ",")
+	sum := sha256.Sum256([]byte(raw))
+	return Key(hex.EncodeToString(sum[:]))
+}
+
+func sortedCopy(items []string) []string {
+	out := make([]string, len(items))
+	copy(out, items)
+	sort.Strings(out)
+	return out
+}
+
+// entry is a cache file's on-disk shape: the resolved sessions (with
+// Password/TOTPSecret encrypted, see crypto.go), a monotonic Version
+// identifying this entry's generation, and CreatedAt for TTL expiry.
+type entry struct {
+	Sessions  []*core.CumulocitySession `json:"sessions"`
+	Version   int64                     `json:"version"`
+	CreatedAt time.Time                 `json:"createdAt"`
+}
+
+// Store is a directory of per-key cache files, normally rooted at
+// $XDG_CACHE_HOME/c8y-session-1password.
+type Store struct {
+	dir string
+	key []byte // symmetric key encrypting Password/TOTPSecret at rest
+}
+
+// Open returns the Store rooted at the standard cache directory, creating
+// it (and its encryption key file) on first use.
+func Open() (*Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	key, err := loadOrCreateKey(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, key: key}, nil
+}
+
+// Dir returns the cache directory following the XDG base directory spec,
+// without creating it.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "c8y-session-1password"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "c8y-session-1password"), nil
+}
+
+func (s *Store) path(key Key) string {
+	return filepath.Join(s.dir, string(key)+".json")
+}
+
+// Get returns the sessions cached under key, decrypting Password/
+// TOTPSecret, if an entry exists and is younger than ttl. ok is false on a
+// miss, an expired entry, or any read/parse/decrypt error -- all treated
+// the same way by the caller: fetch fresh and Set the result.
+func (s *Store) Get(key Key, ttl time.Duration) (sessions []*core.CumulocitySession, ok bool) {
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	if time.Since(e.CreatedAt) > ttl {
+		return nil, false
+	}
+
+	for _, session := range e.Sessions {
+		if err := s.decryptSecrets(session); err != nil {
+			return nil, false
+		}
+	}
+	return e.Sessions, true
+}
+
+// Set writes sessions under key as the cache's current entry for it, with
+// Password/TOTPSecret encrypted at rest. sessions itself is left
+// untouched; Set encrypts copies.
+func (s *Store) Set(key Key, sessions []*core.CumulocitySession) error {
+	encrypted := make([]*core.CumulocitySession, len(sessions))
+	for i, session := range sessions {
+		copySession := *session
+		if err := s.encryptSecrets(&copySession); err != nil {
+			return err
+		}
+		encrypted[i] = &copySession
+	}
+
+	data, err := json.Marshal(entry{Sessions: encrypted, Version: time.Now().UnixNano(), CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path(key), data, 0o600)
+}
+
+// InvalidateAll removes every cached entry, forcing the next List to
+// refetch from 1Password. Used by "cache refresh" and whenever the
+// underlying vault list might have changed -- a single entry only knows
+// the vault/tag selector it was built from, not whether the vaults that
+// selector resolves to have since been renamed or added to, so a full
+// invalidation is the safe response to either.
+func (s *Store) InvalidateAll() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, de := range entries {
+		if !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, de.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
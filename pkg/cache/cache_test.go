@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	return store
+}
+
+func TestBuildKey_OrderIndependent(t *testing.T) {
+	a := BuildKey([]string{"Employee", "IT"}, []string{"c8y", "prod"})
+	b := BuildKey([]string{"IT", "Employee"}, []string{"prod", "c8y"})
+	if a != b {
+		t.Errorf("BuildKey order dependence: %q != %q", a, b)
+	}
+
+	c := BuildKey([]string{"Employee"}, []string{"c8y"})
+	if a == c {
+		t.Errorf("BuildKey collided for different inputs: %q", a)
+	}
+}
+
+func TestStore_SetGetRoundTrip(t *testing.T) {
+	store := openTestStore(t)
+	key := BuildKey([]string{"Employee"}, []string{"c8y"})
+
+	sessions := []*core.CumulocitySession{
+		{SessionURI: "op://Employee/test-item", Password: "s3cret", TOTPSecret: "totp-secret"},
+	}
+
+	if err := store.Set(key, sessions); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	got, ok := store.Get(key, time.Minute)
+	if !ok {
+		t.Fatalf("Get() ok = false, want true")
+	}
+	if len(got) != 1 || got[0].Password != "s3cret" || got[0].TOTPSecret != "totp-secret" {
+		t.Errorf("Get() = %+v, want round-tripped session with secrets intact", got)
+	}
+}
+
+func TestStore_Get_ExpiredEntryMisses(t *testing.T) {
+	store := openTestStore(t)
+	key := BuildKey([]string{"Employee"}, []string{"c8y"})
+
+	if err := store.Set(key, []*core.CumulocitySession{{SessionURI: "op://Employee/test-item"}}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	if _, ok := store.Get(key, 0); ok {
+		t.Errorf("Get() with non-positive ttl should always miss")
+	}
+
+	// Let the entry age past a 1ms TTL (rewriting the same bytes keeps its
+	// stored CreatedAt).
+	data, err := os.ReadFile(store.path(key))
+	if err != nil {
+		t.Fatalf("reading cache file: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := os.WriteFile(store.path(key), data, 0o600); err != nil {
+		t.Fatalf("rewriting cache file: %v", err)
+	}
+	if _, ok := store.Get(key, time.Millisecond); ok {
+		t.Errorf("Get() should miss once the entry is older than ttl")
+	}
+}
+
+func TestStore_EncryptsSecretsAtRest(t *testing.T) {
+	store := openTestStore(t)
+	key := BuildKey([]string{"Employee"}, []string{"c8y"})
+
+	if err := store.Set(key, []*core.CumulocitySession{{SessionURI: "op://Employee/test-item", Password: "s3cret-plaintext"}}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	raw, err := os.ReadFile(store.path(key))
+	if err != nil {
+		t.Fatalf("reading cache file: %v", err)
+	}
+	if strings.Contains(string(raw), "s3cret-plaintext") {
+		t.Errorf("cache file stores the password in plaintext: %s", raw)
+	}
+}
+
+func TestStore_InvalidateAll(t *testing.T) {
+	store := openTestStore(t)
+	key := BuildKey([]string{"Employee"}, []string{"c8y"})
+
+	if err := store.Set(key, []*core.CumulocitySession{{SessionURI: "op://Employee/test-item"}}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := store.InvalidateAll(); err != nil {
+		t.Fatalf("InvalidateAll() error: %v", err)
+	}
+	if _, ok := store.Get(key, time.Hour); ok {
+		t.Errorf("Get() should miss after InvalidateAll()")
+	}
+
+	if entries, err := os.ReadDir(store.dir); err != nil {
+		t.Fatalf("reading cache dir: %v", err)
+	} else {
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".json") {
+				t.Errorf("InvalidateAll() left a cache file behind: %s", e.Name())
+			}
+		}
+	}
+}
+
+func TestDir_UsesXDGCacheHome(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", base)
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error: %v", err)
+	}
+	if want := filepath.Join(base, "c8y-session-1password"); dir != want {
+		t.Errorf("Dir() = %q, want %q", dir, want)
+	}
+}
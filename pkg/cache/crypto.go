@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// keyFileName stores a locally generated AES-256 key encrypting cached
+// Password/TOTPSecret fields at rest. A full OS keyring integration would
+// need a per-platform backend (Keychain/libsecret/Credential Manager) and
+// its own dependency; this local key file, generated once and written
+// with 0600 permissions, gives the same protection against another user
+// or an unencrypted backup reading the cache directly, without one.
+const keyFileName = "cache.key"
+
+func loadOrCreateKey(dir string) ([]byte, error) {
+	path := filepath.Join(dir, keyFileName)
+
+	if data, err := os.ReadFile(path); err == nil && len(data) == 32 {
+		return data, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating cache encryption key: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("writing cache encryption key: %w", err)
+	}
+	return key, nil
+}
+
+func (s *Store) encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (s *Store) decrypt(encoded string) (string, error) {
+	if encoded == "" {
+		return "", nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", errors.New("cache: ciphertext too short")
+	}
+
+	nonce, rest := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, rest, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (s *Store) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecrets replaces session's Password and TOTPSecret with their
+// encrypted form, in place.
+func (s *Store) encryptSecrets(session *core.CumulocitySession) error {
+	password, err := s.encrypt(session.Password)
+	if err != nil {
+		return err
+	}
+	totpSecret, err := s.encrypt(session.TOTPSecret)
+	if err != nil {
+		return err
+	}
+	session.Password = password
+	session.TOTPSecret = totpSecret
+	return nil
+}
+
+// decryptSecrets reverses encryptSecrets, in place.
+func (s *Store) decryptSecrets(session *core.CumulocitySession) error {
+	password, err := s.decrypt(session.Password)
+	if err != nil {
+		return err
+	}
+	totpSecret, err := s.decrypt(session.TOTPSecret)
+	if err != nil {
+		return err
+	}
+	session.Password = password
+	session.TOTPSecret = totpSecret
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package config loads user-facing module configuration from
+// $XDG_CONFIG_HOME/c8y-session-1password/config.json (falling back to
+// ~/.config on platforms without XDG_CONFIG_HOME set).
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// EnvironmentRule classifies a session's Host into an environment label
+// (e.g. "prod", "staging", "dev") using a regular expression. Rules are
+// evaluated in order; the first match wins.
+type EnvironmentRule struct {
+	Pattern string `json:"pattern"`
+	Label   string `json:"label"`
+	Light   string `json:"light,omitempty"` // Foreground color for light terminals
+	Dark    string `json:"dark,omitempty"`  // Foreground color for dark terminals
+}
+
+// Templates holds Go text/template sources (see pkg/core.TemplateFuncs for
+// the available helpers) that override how a session is rendered. Title
+// and Description override CumulocitySession's picker rendering; Name and
+// URI override the Name/SessionURI MapToSessions assigns when building
+// sessions from an item, letting operators derive them from the item
+// title, vault, tags, or matched URL instead of the hard-coded naming
+// rules; Document is used by the root command's "--output template" mode
+// to produce the final output document. Any field may be left empty to
+// keep the built-in formatting for that part.
+type Templates struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`
+	URI         string `json:"uri,omitempty"`
+	Document    string `json:"document,omitempty"`
+}
+
+// Config holds the module's user-editable settings.
+type Config struct {
+	EnvironmentRules []EnvironmentRule `json:"environmentRules,omitempty"`
+	Templates        Templates         `json:"templates,omitempty"`
+	URLRules         []URLRule         `json:"urlRules,omitempty"`
+}
+
+// DefaultEnvironmentRules classifies the common go-c8y-cli naming
+// conventions: *.prod.*/*-prod-* is production, staging/stage is staging,
+// and dev/test/sandbox hosts are development.
+func DefaultEnvironmentRules() []EnvironmentRule {
+	return []EnvironmentRule{
+		{Pattern: `(?i)(^|[.\-])(prod|production)([.\-]|$)`, Label: "prod", Light: "#9A1B1B", Dark: "#FF5F5F"},
+		{Pattern: `(?i)(^|[.\-])(stage|staging)([.\-]|$)`, Label: "staging", Light: "#8A6D00", Dark: "#FFBE00"},
+		{Pattern: `(?i)(^|[.\-])(dev|development|test|sandbox)([.\-]|$)`, Label: "dev", Light: "#1F4E79", Dark: "#3A8BDB"},
+	}
+}
+
+// Default returns the built-in configuration used when no config file is
+// present or it fails to parse.
+func Default() *Config {
+	return &Config{EnvironmentRules: DefaultEnvironmentRules()}
+}
+
+// configDir resolves the module's config directory following the XDG base
+// directory spec.
+func configDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "c8y-session-1password"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "c8y-session-1password"), nil
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads the config file, falling back to Default() if it doesn't
+// exist or can't be parsed. A missing config file is not an error.
+func Load() *Config {
+	path, err := Path()
+	if err != nil {
+		return Default()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Default()
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return Default()
+	}
+
+	if len(cfg.EnvironmentRules) == 0 {
+		cfg.EnvironmentRules = DefaultEnvironmentRules()
+	}
+
+	return cfg
+}
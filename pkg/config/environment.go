@@ -0,0 +1,42 @@
+package config
+
+import "regexp"
+
+// EnvironmentClassifier evaluates a compiled EnvironmentRule set against a
+// session Host to pick a badge label and color.
+type EnvironmentClassifier struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	re    *regexp.Regexp
+	label string
+	light string
+	dark  string
+}
+
+// NewEnvironmentClassifier compiles the given rules, silently skipping any
+// with an invalid regex so a single bad pattern in the config file doesn't
+// break the whole picker.
+func NewEnvironmentClassifier(rules []EnvironmentRule) *EnvironmentClassifier {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, compiledRule{re: re, label: rule.Label, light: rule.Light, dark: rule.Dark})
+	}
+	return &EnvironmentClassifier{rules: compiled}
+}
+
+// Classify returns the label and adaptive colors for host, and false if no
+// rule matched.
+func (c *EnvironmentClassifier) Classify(host string) (label, light, dark string, matched bool) {
+	for _, rule := range c.rules {
+		if rule.re.MatchString(host) {
+			return rule.label, rule.light, rule.dark, true
+		}
+	}
+	return "", "", "", false
+}
@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestEnvironmentClassifierDefaults(t *testing.T) {
+	classifier := NewEnvironmentClassifier(DefaultEnvironmentRules())
+
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{"prod.cumulocity.com", "prod"},
+		{"tenant-prod-01.eu-latest.cumulocity.com", "prod"},
+		{"staging.cumulocity.com", "staging"},
+		{"tenant.stage.c8y.io", "staging"},
+		{"dev.cumulocity.com", "dev"},
+		{"integration-tests-01.dtm-dev.stage.c8y.io", "staging"},
+		{"unclassified.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		label, _, _, matched := classifier.Classify(tt.host)
+		if tt.expected == "" {
+			if matched {
+				t.Errorf("Classify(%q) = %q, expected no match", tt.host, label)
+			}
+			continue
+		}
+		if !matched || label != tt.expected {
+			t.Errorf("Classify(%q) = %q, matched=%v; expected %q", tt.host, label, matched, tt.expected)
+		}
+	}
+}
+
+func TestEnvironmentClassifierSkipsInvalidPattern(t *testing.T) {
+	classifier := NewEnvironmentClassifier([]EnvironmentRule{
+		{Pattern: "(", Label: "broken"},
+		{Pattern: "prod", Label: "prod"},
+	})
+
+	label, _, _, matched := classifier.Classify("prod.example.com")
+	if !matched || label != "prod" {
+		t.Errorf("expected valid rule to still match, got label=%q matched=%v", label, matched)
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+)
+
+// URLRule matches a session's URL (by href or label) to classify its
+// environment, contribute extra tags, and optionally rename the session it
+// produces. Match is a regular expression; capture groups are available in
+// Environment and NameTemplate as "${1}", "${2}", etc. Rules are evaluated
+// in descending Priority order (ties keep config file order), first match
+// wins.
+type URLRule struct {
+	Match        string   `json:"match"`
+	Environment  string   `json:"environment,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	NameTemplate string   `json:"nameTemplate,omitempty"`
+	Priority     int      `json:"priority,omitempty"`
+}
+
+type compiledURLRule struct {
+	rule URLRule
+	re   *regexp.Regexp
+}
+
+// URLRuleEngine evaluates a compiled, priority-sorted URLRule set against a
+// session's URL. It implements core.URLRuleMatcher.
+type URLRuleEngine struct {
+	rules []compiledURLRule
+}
+
+// NewURLRuleEngine compiles rules, silently skipping any with an invalid
+// regex so one bad pattern doesn't break the whole engine, and sorts them
+// by descending Priority.
+func NewURLRuleEngine(rules []URLRule) *URLRuleEngine {
+	compiled := make([]compiledURLRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, compiledURLRule{rule: rule, re: re})
+	}
+	sort.SliceStable(compiled, func(i, j int) bool {
+		return compiled[i].rule.Priority > compiled[j].rule.Priority
+	})
+	return &URLRuleEngine{rules: compiled}
+}
+
+// Match evaluates href and label against the engine's rules in priority
+// order and returns the first (highest-priority) match, with capture
+// groups from whichever of href/label matched substituted into Environment
+// and NameTemplate.
+func (e *URLRuleEngine) Match(href, label string) (environment string, tags []string, name string, matched bool) {
+	rule, expanded, matched := e.match(href, label)
+	if !matched {
+		return "", nil, "", false
+	}
+	return expanded.Environment, rule.Tags, expanded.NameTemplate, true
+}
+
+// MatchVerbose is like Match, but also returns the URLRule that fired (and
+// its expanded Environment/NameTemplate), for reporting which rule matched
+// a given URL (see the root command's --dry-run-rules mode).
+func (e *URLRuleEngine) MatchVerbose(href, label string) (rule URLRule, expanded URLRule, matched bool) {
+	return e.match(href, label)
+}
+
+func (e *URLRuleEngine) match(href, label string) (rule URLRule, expanded URLRule, matched bool) {
+	for _, cr := range e.rules {
+		subject, loc := href, cr.re.FindStringSubmatchIndex(href)
+		if loc == nil {
+			subject, loc = label, cr.re.FindStringSubmatchIndex(label)
+		}
+		if loc == nil {
+			continue
+		}
+
+		expand := func(tmpl string) string {
+			if tmpl == "" {
+				return ""
+			}
+			return string(cr.re.ExpandString(nil, tmpl, subject, loc))
+		}
+
+		resolved := cr.rule
+		resolved.Environment = expand(cr.rule.Environment)
+		resolved.NameTemplate = expand(cr.rule.NameTemplate)
+		return cr.rule, resolved, true
+	}
+	return URLRule{}, URLRule{}, false
+}
@@ -0,0 +1,70 @@
+package config
+
+import "testing"
+
+func TestURLRuleEngineCaptureGroups(t *testing.T) {
+	engine := NewURLRuleEngine([]URLRule{
+		{Match: `https?://([^.]+)\.cumulocity\.com`, Environment: "tenant-${1}", NameTemplate: "${1} (Cumulocity)"},
+	})
+
+	environment, tags, name, matched := engine.Match("https://acme.cumulocity.com", "")
+	if !matched {
+		t.Fatalf("expected a match")
+	}
+	if environment != "tenant-acme" {
+		t.Errorf("expected environment %q, got %q", "tenant-acme", environment)
+	}
+	if name != "acme (Cumulocity)" {
+		t.Errorf("expected name %q, got %q", "acme (Cumulocity)", name)
+	}
+	if len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
+
+func TestURLRuleEnginePriorityOrder(t *testing.T) {
+	engine := NewURLRuleEngine([]URLRule{
+		{Match: `-prod-`, Environment: "prod", Priority: 1},
+		{Match: `cumulocity\.com`, Environment: "generic", Priority: 0},
+	})
+
+	environment, _, _, matched := engine.Match("https://tenant-prod-01.cumulocity.com", "")
+	if !matched || environment != "prod" {
+		t.Errorf("expected the higher-priority rule to win with environment %q, got %q (matched=%v)", "prod", environment, matched)
+	}
+}
+
+func TestURLRuleEngineMatchesLabelWhenHrefMisses(t *testing.T) {
+	engine := NewURLRuleEngine([]URLRule{
+		{Match: "(?i)staging", Environment: "staging", Tags: []string{"non-prod"}},
+	})
+
+	environment, tags, _, matched := engine.Match("https://10.0.0.1", "Staging")
+	if !matched || environment != "staging" {
+		t.Errorf("expected label match to yield environment %q, got %q (matched=%v)", "staging", environment, matched)
+	}
+	if len(tags) != 1 || tags[0] != "non-prod" {
+		t.Errorf("expected tags [non-prod], got %v", tags)
+	}
+}
+
+func TestURLRuleEngineSkipsInvalidPattern(t *testing.T) {
+	engine := NewURLRuleEngine([]URLRule{
+		{Match: "(", Environment: "broken"},
+		{Match: "prod", Environment: "prod"},
+	})
+
+	environment, _, _, matched := engine.Match("https://prod.example.com", "")
+	if !matched || environment != "prod" {
+		t.Errorf("expected valid rule to still match, got environment=%q matched=%v", environment, matched)
+	}
+}
+
+func TestURLRuleEngineNoMatch(t *testing.T) {
+	engine := NewURLRuleEngine([]URLRule{{Match: "prod", Environment: "prod"}})
+
+	_, _, _, matched := engine.Match("https://dev.example.com", "")
+	if matched {
+		t.Errorf("expected no match")
+	}
+}
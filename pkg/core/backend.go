@@ -0,0 +1,71 @@
+package core
+
+import "sort"
+
+// SecretBackend is implemented by anything that can supply login items for
+// session building -- today that's the 1Password CLI (pkg/onepassword) and
+// a HashiCorp Vault KV v2 mount (pkg/vaultsecrets). Both translate their
+// native item representation into the backend-agnostic Item/ItemFields/
+// URLSource types so that tag/vault filtering and session naming
+// (MapToSessions) behave identically regardless of where the secret lives.
+type SecretBackend interface {
+	// ListItems returns every item in vault matching tags (tags may be
+	// empty to mean "no tag filter"), each already split into its fields
+	// and URL sources for MapToSessions.
+	ListItems(vault string, tags []string) ([]BackendItem, error)
+
+	// GetItem returns a single item by vault and item identifier (name or
+	// ID, meaning is backend-specific).
+	GetItem(vault, item string) (BackendItem, error)
+
+	// ResolveURI parses a session URI understood by this backend (e.g.
+	// "op://vault/item" or "vault://mount/path") into a vault and item
+	// identifier pair suitable for GetItem.
+	ResolveURI(uri string) (vault, item string, err error)
+
+	// Scheme is the URI scheme this backend's sessions are built with
+	// (passed to MapToSessionsScheme), e.g. "op" or "vault".
+	Scheme() string
+}
+
+// BackendItem bundles one item's backend-agnostic data, everything
+// MapToSessions needs to build CumulocitySessions from it.
+type BackendItem struct {
+	Item   Item
+	Fields ItemFields
+	URLs   []URLSource
+	// VaultName is the display name of the vault/mount the item came from,
+	// which may differ from Item.Vault.Name when callers looked the item up
+	// by ID (1Password) or the mount's logical name is distinct from its
+	// path (Vault).
+	VaultName string
+}
+
+// ListFromBackends fans ListItems out across every backend, merges the
+// results, and maps each item to sessions with its backend's URI scheme,
+// sorted the same way a single backend's listing is (by display URL). A
+// failure from one backend does not prevent the others' results from being
+// returned; each backend's error is collected into the returned slice,
+// alongside the sessions collected so far.
+func ListFromBackends(backends []SecretBackend, vault string, tags []string) ([]*CumulocitySession, []error) {
+	var sessions []*CumulocitySession
+	var errs []error
+
+	for _, backend := range backends {
+		items, err := backend.ListItems(vault, tags)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, item := range items {
+			sessions = append(sessions, MapToSessionsScheme(backend.Scheme(), item.Item, item.Fields, item.URLs, item.VaultName, tags)...)
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return NormalizeDisplayURL(sessions[i].Host) < NormalizeDisplayURL(sessions[j].Host)
+	})
+
+	return sessions, errs
+}
@@ -0,0 +1,73 @@
+package core
+
+import "strings"
+
+// FieldDiff records a single field that differs between a local session
+// and the current state of its matching 1Password item.
+type FieldDiff struct {
+	Field  string `json:"field"`
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+}
+
+// SessionDiff reports how a local session has drifted from the current
+// state of its matching 1Password item.
+type SessionDiff struct {
+	SessionURI string      `json:"sessionUri"`
+	Fields     []FieldDiff `json:"fields,omitempty"`
+}
+
+// Drifted reports whether any field differs.
+func (d *SessionDiff) Drifted() bool { return len(d.Fields) > 0 }
+
+// NewSessionDiff compares local against remoteSessions -- every session
+// MapToSessions produced from the matching item's current URLs -- and
+// reports field-level drift: the URL set, tenant, username, tags, and TOTP
+// presence. local.Host is compared against the full URL set first (a
+// session whose URL is still among the item's URLs, just reordered, isn't
+// drift); every other field is then compared against whichever remote
+// session shares local's Host, falling back to the first remote session if
+// none does.
+func NewSessionDiff(local *CumulocitySession, remoteSessions []*CumulocitySession) *SessionDiff {
+	diff := &SessionDiff{SessionURI: local.SessionURI}
+
+	if len(remoteSessions) == 0 {
+		diff.Fields = append(diff.Fields, FieldDiff{Field: "host", Local: local.Host, Remote: ""})
+		return diff
+	}
+
+	remoteHosts := make([]string, len(remoteSessions))
+	var remote *CumulocitySession
+	for i, s := range remoteSessions {
+		remoteHosts[i] = s.Host
+		if s.Host == local.Host {
+			remote = s
+		}
+	}
+	if remote == nil {
+		diff.Fields = append(diff.Fields, FieldDiff{Field: "host", Local: local.Host, Remote: strings.Join(remoteHosts, ", ")})
+		remote = remoteSessions[0]
+	}
+
+	addIfDiffer := func(field, localValue, remoteValue string) {
+		if localValue != remoteValue {
+			diff.Fields = append(diff.Fields, FieldDiff{Field: field, Local: localValue, Remote: remoteValue})
+		}
+	}
+
+	addIfDiffer("tenant", local.Tenant, remote.Tenant)
+	addIfDiffer("username", local.Username, remote.Username)
+	addIfDiffer("tags", strings.Join(NormalizeTags(local.Tags), ","), strings.Join(NormalizeTags(remote.Tags), ","))
+	addIfDiffer("totp", totpPresence(local.TOTPSecret), totpPresence(remote.TOTPSecret))
+
+	return diff
+}
+
+// totpPresence renders a TOTP secret as "present"/"absent" for FieldDiff,
+// since the secret itself shouldn't be compared or echoed back.
+func totpPresence(secret string) string {
+	if secret == "" {
+		return "absent"
+	}
+	return "present"
+}
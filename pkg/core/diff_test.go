@@ -0,0 +1,93 @@
+package core
+
+import "testing"
+
+func TestNewSessionDiff_NoDrift(t *testing.T) {
+	local := &CumulocitySession{
+		SessionURI: "op://vault/item",
+		Host:       "https://tenant.eu-latest.cumulocity.com",
+		Tenant:     "t12345",
+		Username:   "admin",
+		Tags:       []string{"c8y"},
+	}
+	remote := &CumulocitySession{
+		Host:     "https://tenant.eu-latest.cumulocity.com",
+		Tenant:   "t12345",
+		Username: "admin",
+		Tags:     []string{"c8y"},
+	}
+
+	diff := NewSessionDiff(local, []*CumulocitySession{remote})
+	if diff.Drifted() {
+		t.Errorf("expected no drift, got %+v", diff.Fields)
+	}
+}
+
+func TestNewSessionDiff_FieldDrift(t *testing.T) {
+	local := &CumulocitySession{
+		SessionURI: "op://vault/item",
+		Host:       "https://tenant.eu-latest.cumulocity.com",
+		Tenant:     "t12345",
+		Username:   "admin",
+	}
+	remote := &CumulocitySession{
+		Host:     "https://tenant.eu-latest.cumulocity.com",
+		Tenant:   "t99999",
+		Username: "admin2",
+	}
+
+	diff := NewSessionDiff(local, []*CumulocitySession{remote})
+	if !diff.Drifted() {
+		t.Fatal("expected drift to be detected")
+	}
+
+	got := map[string]FieldDiff{}
+	for _, f := range diff.Fields {
+		got[f.Field] = f
+	}
+
+	if f, ok := got["tenant"]; !ok || f.Local != "t12345" || f.Remote != "t99999" {
+		t.Errorf("tenant diff = %+v, want Local=t12345 Remote=t99999", f)
+	}
+	if f, ok := got["username"]; !ok || f.Local != "admin" || f.Remote != "admin2" {
+		t.Errorf("username diff = %+v, want Local=admin Remote=admin2", f)
+	}
+	if _, ok := got["host"]; ok {
+		t.Error("host should not be reported as drifted when it matches a remote session")
+	}
+}
+
+func TestNewSessionDiff_HostNotAmongRemoteURLs(t *testing.T) {
+	local := &CumulocitySession{SessionURI: "op://vault/item", Host: "https://old.example.com"}
+	remote := &CumulocitySession{Host: "https://new.example.com"}
+
+	diff := NewSessionDiff(local, []*CumulocitySession{remote})
+	if !diff.Drifted() {
+		t.Fatal("expected drift when local host isn't among the item's URLs")
+	}
+	if diff.Fields[0].Field != "host" || diff.Fields[0].Remote != "https://new.example.com" {
+		t.Errorf("host diff = %+v", diff.Fields[0])
+	}
+}
+
+func TestNewSessionDiff_TOTPPresence(t *testing.T) {
+	local := &CumulocitySession{SessionURI: "op://vault/item", Host: "https://t.example.com", TOTPSecret: "SECRET"}
+	remote := &CumulocitySession{Host: "https://t.example.com"}
+
+	diff := NewSessionDiff(local, []*CumulocitySession{remote})
+	if !diff.Drifted() {
+		t.Fatal("expected drift for TOTP presence")
+	}
+	if diff.Fields[0].Field != "totp" || diff.Fields[0].Local != "present" || diff.Fields[0].Remote != "absent" {
+		t.Errorf("totp diff = %+v, want Local=present Remote=absent", diff.Fields[0])
+	}
+}
+
+func TestNewSessionDiff_NoRemoteSessions(t *testing.T) {
+	local := &CumulocitySession{SessionURI: "op://vault/item", Host: "https://t.example.com"}
+
+	diff := NewSessionDiff(local, nil)
+	if !diff.Drifted() {
+		t.Fatal("expected drift when the item has no matching remote sessions")
+	}
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+// Filter mode values accepted by FilterSessions (and surfaced as the
+// --filter-mode flag): "fuzzy" ranks sessions by relevance, "substring"
+// keeps the original plain case-insensitive substring match for scripts
+// that depend on exact legacy behavior.
+const (
+	FilterModeFuzzy     = "fuzzy"
+	FilterModeSubstring = "substring"
+)
+
+// Per-field weights used when scoring a session against a query. Host and
+// Name carry the most signal for a human recognizing a session at a
+// glance, so a match there outweighs an equally strong match buried in a
+// tag.
+const (
+	fuzzyWeightName     = 10
+	fuzzyWeightHost     = 10
+	fuzzyWeightItemName = 6
+	fuzzyWeightUsername = 6
+	fuzzyWeightTenant   = 6
+	fuzzyWeightTags     = 3
+)
+
+// isWordBoundary reports whether r marks the start of a new "word" inside a
+// candidate string for the purposes of FuzzyScore's boundary bonus.
+func isWordBoundary(r rune) bool {
+	switch r {
+	case '.', '-', '/', '_', ' ', ':':
+		return true
+	}
+	return false
+}
+
+// FuzzyScore computes a Smith-Waterman-style subsequence match of query
+// against candidate (both compared case-insensitively): query's runes are
+// walked greedily through candidate, awarding +16 for a match at a word
+// boundary (the start of candidate, or right after '.', '-', '/', '_', a
+// space, or ':'), +8 for a match immediately following the previous one,
+// +1 for any other match, and -3 for each candidate character skipped
+// between two matches. ok is false if some rune of query never occurs in
+// candidate at all, in which case score is meaningless.
+func FuzzyScore(query, candidate string) (score int, ok bool) {
+	score, _, ok = FuzzyMatch(query, candidate)
+	return score, ok
+}
+
+// FuzzyMatch is FuzzyScore, also returning the rune indices into candidate
+// that query matched, in order -- for a caller (the picker) that wants to
+// highlight just those characters in the rendered field instead of only
+// ranking by score.
+func FuzzyMatch(query, candidate string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	c := []rune(strings.ToLower(candidate))
+
+	qi := 0
+	last := -1
+	for ci := 0; ci < len(c) && qi < len(q); ci++ {
+		if c[ci] != q[qi] {
+			continue
+		}
+
+		switch {
+		case ci == 0 || isWordBoundary(c[ci-1]):
+			score += 16
+		case last == ci-1:
+			score += 8
+		default:
+			score++
+		}
+		if last >= 0 {
+			score -= 3 * (ci - last - 1)
+		}
+		last = ci
+		qi++
+		positions = append(positions, ci)
+	}
+
+	return score, positions, qi == len(q)
+}
+
+// scoredField is one of a session's searchable fields, weighted by how much
+// signal a match there carries (see sessionFuzzyScore/RankSessions).
+type scoredField struct {
+	name   string
+	value  string
+	weight int
+}
+
+// sessionScoredFields lists session's searchable fields and their weights,
+// shared by sessionFuzzyScore (score only) and RankSessions (score plus
+// matched-rune positions, for highlighting).
+func sessionScoredFields(session *CumulocitySession) []scoredField {
+	return []scoredField{
+		{"Name", session.Name, fuzzyWeightName},
+		{"Host", session.Host, fuzzyWeightHost},
+		{"ItemName", session.ItemName, fuzzyWeightItemName},
+		{"Username", session.Username, fuzzyWeightUsername},
+		{"Tenant", session.Tenant, fuzzyWeightTenant},
+		{"Tags", strings.Join(session.Tags, " "), fuzzyWeightTags},
+	}
+}
+
+// sessionFuzzyScore returns the highest weighted FuzzyScore across a
+// session's searchable fields (Name, ItemName, Host, Username, Tenant, and
+// its joined Tags), or 0 if filter doesn't match any of them.
+func sessionFuzzyScore(session *CumulocitySession, filter string) int {
+	best := 0
+	for _, f := range sessionScoredFields(session) {
+		if f.value == "" {
+			continue
+		}
+		raw, ok := FuzzyScore(filter, f.value)
+		if !ok {
+			continue
+		}
+		if weighted := raw * f.weight; weighted > best {
+			best = weighted
+		}
+	}
+	return best
+}
+
+// ScoredSession pairs a session with the fuzzy match RankSessions computed
+// for it: Score is the same weighted score FilterSessions' fuzzy mode
+// ranks by, Field names which session field produced it (e.g. "Host"), and
+// Positions are the matched rune indices into that field, for a caller
+// that wants to highlight them in Title()/Description(). Score is 0 and
+// Field/Positions are empty when query matched nothing.
+type ScoredSession struct {
+	Session   *CumulocitySession
+	Score     int
+	Field     string
+	Positions []int
+}
+
+// RankSessions scores every session against query exactly as FilterSessions'
+// fuzzy mode does, but -- unlike FilterSessions -- never drops a
+// non-matching session: every input session comes back as a ScoredSession,
+// sorted by Score descending (ties broken by input order), so a caller like
+// the interactive picker can dim or hide low-score entries itself and
+// highlight Positions, rather than losing that information the moment
+// FilterSessions filters it out.
+func RankSessions(sessions []*CumulocitySession, query string) []ScoredSession {
+	ranked := make([]ScoredSession, len(sessions))
+	for i, session := range sessions {
+		scored := ScoredSession{Session: session}
+		for _, f := range sessionScoredFields(session) {
+			if f.value == "" {
+				continue
+			}
+			raw, positions, ok := FuzzyMatch(query, f.value)
+			if !ok {
+				continue
+			}
+			if weighted := raw * f.weight; weighted > scored.Score {
+				scored.Score = weighted
+				scored.Field = f.name
+				scored.Positions = positions
+			}
+		}
+		ranked[i] = scored
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// FilterSessions filters sessions by a query string. mode selects the
+// matching strategy:
+//
+//   - FilterModeSubstring ("substring") is the original plain
+//     case-insensitive substring match against Name, ItemName, and Host,
+//     in input order.
+//   - anything else, including "" and FilterModeFuzzy ("fuzzy"), scores
+//     every session with FuzzyScore against Name, ItemName, Host,
+//     Username, Tenant, and Tags, keeping only sessions with a positive
+//     score and sorting the rest by score descending (ties broken by
+//     input order). The returned sessions are copies of the inputs with
+//     Score set to the score they were ranked with, for callers that want
+//     to render it -- the inputs themselves are never modified.
+//
+// An empty filter returns sessions unfiltered in both modes (the same
+// pointers, unscored).
+func FilterSessions(sessions []*CumulocitySession, filter string, mode string) []*CumulocitySession {
+	if filter == "" {
+		return sessions
+	}
+
+	if mode == FilterModeSubstring {
+		return filterSessionsBySubstring(sessions, filter)
+	}
+
+	type ranked struct {
+		session *CumulocitySession
+		score   int
+	}
+	candidates := make([]ranked, 0, len(sessions))
+	for _, session := range sessions {
+		if score := sessionFuzzyScore(session, filter); score > 0 {
+			// Score a copy rather than the input session itself: callers
+			// (e.g. cmd/serve.go's daemon) may hold the same *CumulocitySession
+			// cached and shared across concurrent requests, so mutating it in
+			// place would be a data race.
+			scored := *session
+			scored.Score = score
+			candidates = append(candidates, ranked{&scored, score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	filtered := make([]*CumulocitySession, len(candidates))
+	for i, r := range candidates {
+		filtered[i] = r.session
+	}
+	return filtered
+}
+
+func filterSessionsBySubstring(sessions []*CumulocitySession, filter string) []*CumulocitySession {
+	filter = strings.ToLower(filter)
+	var filtered []*CumulocitySession
+
+	for _, session := range sessions {
+		if strings.Contains(strings.ToLower(session.Name), filter) ||
+			strings.Contains(strings.ToLower(session.ItemName), filter) ||
+			strings.Contains(strings.ToLower(session.Host), filter) {
+			filtered = append(filtered, session)
+		}
+	}
+
+	return filtered
+}
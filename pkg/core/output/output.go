@@ -0,0 +1,259 @@
+// Package output renders a list of Cumulocity sessions in the
+// non-interactive formats the "list" command's --output flag supports:
+// json, yaml, jsonpath=<expr>, and go-template=<text>, mirroring the
+// "kubectl get -o" family.
+//
+// "table" (the --output default) and --dump's "table"/"tsv" are
+// deliberately not handled here: an --output of "table" or "" launches
+// the interactive picker (see cmd/list.go), and --dump table/tsv renders
+// through pkg/core/picker.Dump instead, which also supports --columns.
+// Encode's own table/tsv renderer was removed because nothing could ever
+// reach it -- keeping one table renderer, in the package that already
+// owns DefaultColumns and the picker's row layout, avoids the two
+// silently drifting apart.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/picker"
+	"gopkg.in/yaml.v3"
+)
+
+// Envelope is the stable shape behind the json/yaml/jsonpath/go-template
+// formats: every matching session plus the query metadata that produced
+// them, so scripts piping the output into jq/yq (or c8y itself) get the
+// query context for free.
+type Envelope struct {
+	Items    []*core.CumulocitySession `json:"items" yaml:"items"`
+	Metadata picker.PickerMetadata     `json:"metadata" yaml:"metadata"`
+}
+
+// NewEnvelope builds an Envelope from sessions, masking secrets unless
+// reveal is set -- the same obfuscation rule the interactive picker and
+// single-session output formats use.
+func NewEnvelope(sessions []*core.CumulocitySession, metadata picker.PickerMetadata, reveal bool) Envelope {
+	items := make([]*core.CumulocitySession, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, maskSecrets(session, reveal))
+	}
+	return Envelope{Items: items, Metadata: metadata}
+}
+
+func maskSecrets(session *core.CumulocitySession, reveal bool) *core.CumulocitySession {
+	masked := *session
+	if reveal {
+		return &masked
+	}
+	if masked.Password != "" {
+		masked.Password = "***"
+	}
+	if masked.TOTP != "" {
+		masked.TOTP = "***"
+	}
+	if masked.TOTPSecret != "" {
+		masked.TOTPSecret = "***"
+	}
+	if masked.AccessToken != "" {
+		masked.AccessToken = "***"
+	}
+	if masked.OIDCClientSecret != "" {
+		masked.OIDCClientSecret = "***"
+	}
+	if masked.RefreshToken != "" {
+		masked.RefreshToken = "***"
+	}
+	return &masked
+}
+
+// Format is a parsed --output value: a keyword ("table", "json", "yaml"),
+// or a keyword/expression pair for "jsonpath=..." and "go-template=...".
+type Format struct {
+	Name string
+	Expr string
+}
+
+// ParseFormat splits an --output flag value like "jsonpath={.items[*].host}"
+// into its keyword and expression.
+func ParseFormat(value string) Format {
+	if name, expr, ok := strings.Cut(value, "="); ok {
+		return Format{Name: name, Expr: expr}
+	}
+	return Format{Name: value}
+}
+
+// Encode writes env to w in the format described by f. "table" (and the
+// empty default) isn't handled here -- see the package doc comment.
+func Encode(w io.Writer, env Envelope, f Format) error {
+	switch f.Name {
+	case "json":
+		return encodeJSON(w, env)
+	case "yaml":
+		return encodeYAML(w, env)
+	case "jsonpath":
+		return encodeJSONPath(w, env, f.Expr)
+	case "go-template":
+		return encodeGoTemplate(w, env, f.Expr)
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml, jsonpath=..., go-template=...)", f.Name)
+	}
+}
+
+func encodeJSON(w io.Writer, env Envelope) error {
+	out, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
+
+func encodeYAML(w io.Writer, env Envelope) error {
+	out, err := yaml.Marshal(env)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, string(out))
+	return err
+}
+
+func encodeGoTemplate(w io.Writer, env Envelope, text string) error {
+	if text == "" {
+		return fmt.Errorf(`go-template output requires a template, e.g. go-template={{range .Items}}{{.Host}}{{"\n"}}{{end}}`)
+	}
+	tmpl, err := texttemplate.New("list").Funcs(core.TemplateFuncs()).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, env)
+}
+
+func encodeJSONPath(w io.Writer, env Envelope, expr string) error {
+	if expr == "" {
+		return fmt.Errorf("jsonpath output requires an expression, e.g. jsonpath={.items[*].host}")
+	}
+
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+
+	results, err := evalJSONPath(data, expr)
+	if err != nil {
+		return err
+	}
+
+	strs := make([]string, 0, len(results))
+	for _, r := range results {
+		strs = append(strs, fmt.Sprint(r))
+	}
+	_, err = fmt.Fprintln(w, strings.Join(strs, " "))
+	return err
+}
+
+// jsonPathSegment is one "field" or "field[index]" step of a jsonpath
+// expression; index is "*" for "every element" or a decimal string.
+type jsonPathSegment struct {
+	field    string
+	index    string
+	hasIndex bool
+}
+
+// evalJSONPath supports the subset of kubectl-style jsonpath this package
+// needs: dotted field access and "[N]"/"[*]" array indexing, e.g.
+// "{.items[*].host}" or ".metadata.filter". It does not support filter
+// expressions, ranges, or the "..recursive" operator.
+func evalJSONPath(data interface{}, expr string) ([]interface{}, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "{")
+	expr = strings.TrimSuffix(expr, "}")
+	expr = strings.TrimPrefix(expr, ".")
+	if expr == "" {
+		return []interface{}{data}, nil
+	}
+
+	segments, err := splitJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	for _, seg := range segments {
+		var next []interface{}
+		for _, c := range current {
+			vals, err := applyJSONPathSegment(c, seg)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, vals...)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func splitJSONPath(expr string) ([]jsonPathSegment, error) {
+	parts := strings.Split(expr, ".")
+	segments := make([]jsonPathSegment, 0, len(parts))
+	for _, p := range parts {
+		seg := jsonPathSegment{}
+		if idx := strings.IndexByte(p, '['); idx >= 0 {
+			if !strings.HasSuffix(p, "]") {
+				return nil, fmt.Errorf("jsonpath: malformed segment %q", p)
+			}
+			seg.field = p[:idx]
+			seg.index = p[idx+1 : len(p)-1]
+			seg.hasIndex = true
+		} else {
+			seg.field = p
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+func applyJSONPathSegment(data interface{}, seg jsonPathSegment) ([]interface{}, error) {
+	current := data
+	if seg.field != "" {
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("jsonpath: %q is not an object", seg.field)
+		}
+		val, ok := m[seg.field]
+		if !ok {
+			return nil, nil
+		}
+		current = val
+	}
+
+	if !seg.hasIndex {
+		return []interface{}{current}, nil
+	}
+
+	arr, ok := current.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jsonpath: %q is not an array", seg.field)
+	}
+	if seg.index == "*" {
+		return arr, nil
+	}
+
+	n, err := strconv.Atoi(seg.index)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath: invalid index %q", seg.index)
+	}
+	if n < 0 || n >= len(arr) {
+		return nil, fmt.Errorf("jsonpath: index %d out of range for %q", n, seg.field)
+	}
+	return []interface{}{arr[n]}, nil
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/picker"
+)
+
+func testEnvelope() Envelope {
+	return NewEnvelope([]*core.CumulocitySession{
+		{Name: "Production Tenant", Host: "https://tenant1.cumulocity.com", Username: "admin", Password: "secret", Tags: []string{"c8y", "production"}},
+		{Name: "Staging", Host: "https://staging.cumulocity.com", Username: "svc-account", Password: "secret", Tags: []string{"c8y"}},
+	}, picker.PickerMetadata{Tags: []string{"c8y"}}, false)
+}
+
+func TestEncodeUnhandledTableFormat(t *testing.T) {
+	for _, name := range []string{"", "table"} {
+		if err := Encode(&bytes.Buffer{}, testEnvelope(), ParseFormat(name)); err == nil {
+			t.Errorf("expected Encode(%q) to error -- table rendering lives in cmd/list.go and pkg/core/picker.Dump, not here", name)
+		}
+	}
+}
+
+func TestEncodeJSONMasksSecretsByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, testEnvelope(), ParseFormat("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("expected password to be masked, got %s", buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"items"`)) || !bytes.Contains(buf.Bytes(), []byte(`"metadata"`)) {
+		t.Errorf("expected a stable {items, metadata} envelope, got %s", buf.String())
+	}
+}
+
+func TestEncodeJSONRevealsSecretsWhenRequested(t *testing.T) {
+	sessions := []*core.CumulocitySession{{Name: "A", Password: "secret"}}
+	env := NewEnvelope(sessions, picker.PickerMetadata{}, true)
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, env, ParseFormat("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("expected password to be revealed, got %s", buf.String())
+	}
+}
+
+func TestEncodeYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, testEnvelope(), ParseFormat("yaml")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("items:")) {
+		t.Errorf("expected a yaml items list, got %s", buf.String())
+	}
+}
+
+func TestEncodeJSONPath(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, testEnvelope(), ParseFormat("jsonpath={.items[*].host}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://tenant1.cumulocity.com https://staging.cumulocity.com\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncodeJSONPathSingleField(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, testEnvelope(), ParseFormat("jsonpath={.items[0].name}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "Production Tenant\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestEncodeJSONPathRequiresExpression(t *testing.T) {
+	if err := Encode(&bytes.Buffer{}, testEnvelope(), ParseFormat("jsonpath")); err == nil {
+		t.Errorf("expected an error for a missing jsonpath expression")
+	}
+}
+
+func TestEncodeGoTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := Encode(&buf, testEnvelope(), ParseFormat(`go-template={{range .Items}}{{.Host}}{{"\n"}}{{end}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://tenant1.cumulocity.com\nhttps://staging.cumulocity.com\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncodeUnsupportedFormat(t *testing.T) {
+	if err := Encode(&bytes.Buffer{}, testEnvelope(), ParseFormat("csv")); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
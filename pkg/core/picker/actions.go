@@ -0,0 +1,89 @@
+package picker
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// Action names returned by the picker's per-item action submenu. cmd/
+// dispatches on these to decide what to actually do with the session.
+const (
+	ActionUseSession   = "use-session"
+	ActionCopyBaseURL  = "copy-base-url"
+	ActionCopyUsername = "copy-username"
+	ActionOpenBrowser  = "open-browser"
+	ActionReveal       = "reveal-in-1password"
+	ActionEditTags     = "edit-tags"
+)
+
+// actionItem is a list.Item for the right-side action panel.
+type actionItem struct {
+	id          string
+	title       string
+	description string
+}
+
+func (a actionItem) FilterValue() string { return a.title }
+func (a actionItem) Title() string       { return a.title }
+func (a actionItem) Description() string { return a.description }
+
+func sessionActions() []list.Item {
+	return []list.Item{
+		actionItem{id: ActionUseSession, title: "Use session", description: "Return this session to the caller"},
+		actionItem{id: ActionCopyBaseURL, title: "Copy base URL", description: "Copy the session host to the clipboard"},
+		actionItem{id: ActionCopyUsername, title: "Copy username", description: "Copy the session username to the clipboard"},
+		actionItem{id: ActionOpenBrowser, title: "Open in browser", description: "Open the session host in the default browser"},
+		actionItem{id: ActionReveal, title: "Reveal in 1Password app", description: "Open the 1Password item in the desktop app"},
+		actionItem{id: ActionEditTags, title: "Edit tags/description", description: "Edit the item's tags and description in 1Password"},
+	}
+}
+
+type actionKeyMapType struct {
+	choose key.Binding
+	back   key.Binding
+}
+
+var actionKeys = actionKeyMapType{
+	choose: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "choose action"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc", "left"),
+		key.WithHelp("esc/←", "back"),
+	),
+}
+
+// newActionDelegate renders the action panel entries. It mirrors the default
+// delegate's look without the selection checkbox, since the panel is always
+// scoped to a single session.
+func newActionDelegate() list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
+		Foreground(lipgloss.Color("#056AD6")).
+		BorderForeground(lipgloss.Color("#056AD6")).
+		Bold(true)
+
+	help := []key.Binding{actionKeys.choose, actionKeys.back}
+	d.ShortHelpFunc = func() []key.Binding { return help }
+	d.FullHelpFunc = func() [][]key.Binding { return [][]key.Binding{help} }
+
+	return d
+}
+
+func newActionList(session *core.CumulocitySession) list.Model {
+	l := list.New(sessionActions(), newActionDelegate(), 0, 0)
+	l.Title = "Actions: " + session.Title()
+	l.Styles.Title = titleStyle
+	l.SetShowStatusBar(false)
+	return l
+}
+
+// selectedAction returns the action chosen in the action panel, if any.
+func selectedAction(l list.Model) (actionItem, bool) {
+	item, ok := l.SelectedItem().(actionItem)
+	return item, ok
+}
@@ -0,0 +1,46 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func TestSessionActionsCoversExpectedEntries(t *testing.T) {
+	actions := sessionActions()
+	if len(actions) != 6 {
+		t.Fatalf("expected 6 actions, got %d", len(actions))
+	}
+
+	ids := make(map[string]bool, len(actions))
+	for _, item := range actions {
+		a, ok := item.(actionItem)
+		if !ok {
+			t.Fatalf("expected actionItem, got %T", item)
+		}
+		ids[a.id] = true
+	}
+
+	for _, id := range []string{
+		ActionUseSession,
+		ActionCopyBaseURL,
+		ActionCopyUsername,
+		ActionOpenBrowser,
+		ActionReveal,
+		ActionEditTags,
+	} {
+		if !ids[id] {
+			t.Errorf("expected action %q in sessionActions()", id)
+		}
+	}
+}
+
+func TestNewActionListTitle(t *testing.T) {
+	session := &core.CumulocitySession{Host: "https://example.cumulocity.com"}
+	l := newActionList(session)
+
+	expected := "Actions: example.cumulocity.com"
+	if l.Title != expected {
+		t.Errorf("Title = %q, expected %q", l.Title, expected)
+	}
+}
@@ -1,82 +1,243 @@
 package picker
 
 import (
+	"fmt"
+	"io"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/config"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/prefs"
 )
 
-func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
-	d := list.NewDefaultDelegate()
+// checkboxStyle renders the selection glyph in front of a selected item's title.
+var checkboxStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{
+		Light: "#119D11",
+		Dark:  "#FFBE00",
+	})
+
+// favoriteStyle renders the star shown in front of a favorited item's title.
+var favoriteStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{
+		Light: "#C9A227",
+		Dark:  "#F5D547",
+	})
 
-	// Set custom selection highlight colors with adaptive support
-	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("#056AD6")). // Blue text for both light and dark terminals
-		Background(lipgloss.Color("")).        // No background
+var itemDelegateStyles = struct {
+	normalTitle    lipgloss.Style
+	normalDesc     lipgloss.Style
+	selectedTitle  lipgloss.Style
+	selectedDesc   lipgloss.Style
+	unmatchedBadge lipgloss.Style
+	chip           lipgloss.Style
+}{
+	normalTitle: lipgloss.NewStyle().Padding(0, 0, 0, 2),
+	normalDesc: lipgloss.NewStyle().Padding(0, 0, 0, 2).
+		Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"}),
+	selectedTitle: lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
 		BorderForeground(lipgloss.Color("#056AD6")).
-		Bold(true)
-
-	d.Styles.SelectedDesc = d.Styles.SelectedDesc.
-		Foreground(lipgloss.AdaptiveColor{
-			Light: "#1F4E79", // Even darker blue for better readability in light terminals
-			Dark:  "#3A8BDB", // Keep existing lighter blue for dark terminals
-		}).
-		Background(lipgloss.Color("")). // No background
-		BorderForeground(lipgloss.AdaptiveColor{
-			Light: "#1F4E79", // Match border to description text color
-			Dark:  "#3A8BDB",
-		})
-
-	d.UpdateFunc = func(msg tea.Msg, m *list.Model) tea.Cmd {
-		var title string
-
-		if i, ok := m.SelectedItem().(*core.CumulocitySession); ok {
-			title = i.Host
-		} else {
-			return nil
-		}
+		Foreground(lipgloss.Color("#056AD6")).
+		Bold(true).
+		Padding(0, 0, 0, 1),
+	selectedDesc: lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#1F4E79", Dark: "#3A8BDB"}).
+		Foreground(lipgloss.AdaptiveColor{Light: "#1F4E79", Dark: "#3A8BDB"}).
+		Padding(0, 0, 0, 1),
+	unmatchedBadge: lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#606060", Dark: "#A0A0A0"}),
+	chip: lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#3D3D3D", Dark: "#C0C0C0"}).
+		Background(lipgloss.AdaptiveColor{Light: "#E4E4E4", Dark: "#303030"}).
+		Padding(0, 1),
+}
 
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			switch {
-			case key.Matches(msg, keys.choose):
-				return m.NewStatusMessage(statusMessageStyle("You chose " + title))
-
-			case key.Matches(msg, keys.remove):
-				index := m.Index()
-				m.RemoveItem(index)
-				if len(m.Items()) == 0 {
-					keys.remove.SetEnabled(false)
-				}
-				return m.NewStatusMessage(statusMessageStyle("Deleted " + title))
-			case key.Matches(msg, keys.cancel):
-				return tea.Quit
-			}
+// itemDelegate is a custom list.ItemDelegate for CumulocitySession items. It
+// draws a two-line entry: Host + environment badge on line 1, tenant/
+// username/tag chips on line 2, with a leading selection checkbox and the
+// existing blue highlight for the focused row.
+type itemDelegate struct {
+	keys         *delegateKeyMap
+	selection    *selectionState
+	environments *config.EnvironmentClassifier
+	prefs        *prefs.Prefs
+}
+
+func newItemDelegate(keys *delegateKeyMap, selection *selectionState, userPrefs *prefs.Prefs) itemDelegate {
+	cfg := config.Load()
+	return itemDelegate{
+		keys:         keys,
+		selection:    selection,
+		environments: config.NewEnvironmentClassifier(cfg.EnvironmentRules),
+		prefs:        userPrefs,
+	}
+}
+
+func (d itemDelegate) Height() int              { return 2 }
+func (d itemDelegate) Spacing() int             { return 1 }
+func (d itemDelegate) ShortHelp() []key.Binding { return d.helpKeys() }
+func (d itemDelegate) FullHelp() [][]key.Binding {
+	return [][]key.Binding{d.helpKeys()}
+}
+
+func (d itemDelegate) helpKeys() []key.Binding {
+	return []key.Binding{d.keys.choose, d.keys.action, d.keys.toggleSelect, d.keys.selectAll, d.keys.clearSelect, d.keys.favorite, d.keys.remove}
+}
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	session, ok := item.(*core.CumulocitySession)
+	if !ok {
+		return
+	}
+
+	isSelectedRow := index == m.Index()
+
+	checkbox := "[ ] "
+	if d.selection.IsSelected(session) {
+		checkbox = checkboxStyle.Render("[x]") + " "
+	}
+
+	favorite := ""
+	if d.prefs != nil && d.prefs.IsFavorite(session) {
+		favorite = favoriteStyle.Render("★") + " "
+	}
+
+	titleStyle, descStyle := itemDelegateStyles.normalTitle, itemDelegateStyles.normalDesc
+	if isSelectedRow {
+		titleStyle, descStyle = itemDelegateStyles.selectedTitle, itemDelegateStyles.selectedDesc
+	}
+
+	line1 := checkbox + favorite + session.Title() + " " + d.renderEnvironmentBadge(session.Host)
+	line2 := d.renderMetadataLine(session)
+
+	fmt.Fprintf(w, "%s\n%s", titleStyle.Render(line1), descStyle.Render(line2))
+}
+
+// renderEnvironmentBadge classifies the session host using the pluggable
+// (and user-configurable) environment rules and renders a colored badge.
+func (d itemDelegate) renderEnvironmentBadge(host string) string {
+	label, light, dark, matched := d.environments.Classify(host)
+	if !matched {
+		return ""
+	}
+
+	style := itemDelegateStyles.unmatchedBadge
+	if light != "" || dark != "" {
+		style = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: light, Dark: dark}).Bold(true)
+	}
+
+	return style.Render("[" + label + "]")
+}
+
+// renderMetadataLine draws tenant, username, and tag chips for line 2.
+func (d itemDelegate) renderMetadataLine(session *core.CumulocitySession) string {
+	parts := make([]string, 0, 3)
 
+	if session.Tenant != "" {
+		parts = append(parts, "Tenant="+session.Tenant)
+	}
+	if session.Username != "" {
+		parts = append(parts, "User="+session.Username)
+	}
+
+	line := strings.Join(parts, ", ")
+
+	if len(session.Tags) > 0 {
+		chips := make([]string, len(session.Tags))
+		for i, tag := range session.Tags {
+			chips[i] = itemDelegateStyles.chip.Render(tag)
 		}
+		if line != "" {
+			line += "  "
+		}
+		line += strings.Join(chips, " ")
+	}
+
+	return line
+}
 
+func (d itemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	var title string
+
+	if i, ok := m.SelectedItem().(*core.CumulocitySession); ok {
+		title = i.Host
+	} else {
 		return nil
 	}
 
-	help := []key.Binding{keys.choose, keys.remove}
-
-	d.ShortHelpFunc = func() []key.Binding {
-		return help
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
 	}
 
-	d.FullHelpFunc = func() [][]key.Binding {
-		return [][]key.Binding{help}
+	switch {
+	case key.Matches(keyMsg, d.keys.choose), key.Matches(keyMsg, d.keys.action):
+		// Opening the action panel is handled by the outer model
+		// (it needs to swap in the action list), so just let the
+		// key fall through.
+		return nil
+
+	case key.Matches(keyMsg, d.keys.toggleSelect):
+		if session, ok := m.SelectedItem().(*core.CumulocitySession); ok {
+			d.selection.Toggle(session)
+			return m.NewStatusMessage(statusMessageStyle(d.selection.StatusText()))
+		}
+		return nil
+
+	case key.Matches(keyMsg, d.keys.selectAll):
+		for _, listItem := range m.Items() {
+			if session, ok := listItem.(*core.CumulocitySession); ok {
+				d.selection.Select(session)
+			}
+		}
+		return m.NewStatusMessage(statusMessageStyle(d.selection.StatusText()))
+
+	case key.Matches(keyMsg, d.keys.clearSelect):
+		d.selection.Clear()
+		return m.NewStatusMessage(statusMessageStyle("Selection cleared"))
+
+	case key.Matches(keyMsg, d.keys.favorite):
+		if session, ok := m.SelectedItem().(*core.CumulocitySession); ok {
+			d.prefs.ToggleFavorite(session)
+			_ = d.prefs.Save()
+			status := "Added " + title + " to favorites"
+			if !d.prefs.IsFavorite(session) {
+				status = "Removed " + title + " from favorites"
+			}
+			return m.NewStatusMessage(statusMessageStyle(status))
+		}
+		return nil
+
+	case key.Matches(keyMsg, d.keys.remove):
+		index := m.Index()
+		m.RemoveItem(index)
+		if len(m.Items()) == 0 {
+			d.keys.remove.SetEnabled(false)
+		}
+		return m.NewStatusMessage(statusMessageStyle("Deleted " + title))
+
+	case key.Matches(keyMsg, d.keys.cancel):
+		return tea.Quit
 	}
 
-	return d
+	return nil
 }
 
 type delegateKeyMap struct {
-	choose key.Binding
-	remove key.Binding
-	cancel key.Binding
+	choose       key.Binding
+	action       key.Binding
+	remove       key.Binding
+	cancel       key.Binding
+	toggleSelect key.Binding
+	selectAll    key.Binding
+	clearSelect  key.Binding
+	favorite     key.Binding
 }
 
 // Additional short help entries. This satisfies the help.KeyMap interface and
@@ -84,6 +245,11 @@ type delegateKeyMap struct {
 func (d delegateKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		d.choose,
+		d.action,
+		d.toggleSelect,
+		d.selectAll,
+		d.clearSelect,
+		d.favorite,
 		d.remove,
 		d.cancel,
 	}
@@ -95,6 +261,11 @@ func (d delegateKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{
 			d.choose,
+			d.action,
+			d.toggleSelect,
+			d.selectAll,
+			d.clearSelect,
+			d.favorite,
 			d.remove,
 			d.cancel,
 		},
@@ -104,8 +275,12 @@ func (d delegateKeyMap) FullHelp() [][]key.Binding {
 func newDelegateKeyMap() *delegateKeyMap {
 	return &delegateKeyMap{
 		choose: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "choose"),
+			key.WithKeys("enter", "right"),
+			key.WithHelp("enter/→", "actions"),
+		),
+		action: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "actions"),
 		),
 		remove: key.NewBinding(
 			key.WithKeys("x", "backspace"),
@@ -115,5 +290,21 @@ func newDelegateKeyMap() *delegateKeyMap {
 			key.WithKeys("esc", "ctrl+c", "c"),
 			key.WithHelp("esc/ctrl+c/c", "cancel"),
 		),
+		toggleSelect: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "toggle selection"),
+		),
+		selectAll: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "select all"),
+		),
+		clearSelect: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "clear selection"),
+		),
+		favorite: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "toggle favorite"),
+		),
 	}
 }
@@ -0,0 +1,51 @@
+package picker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/config"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/prefs"
+)
+
+func TestItemDelegateHeightAndSpacing(t *testing.T) {
+	d := newItemDelegate(newDelegateKeyMap(), newSelectionState(), &prefs.Prefs{})
+
+	if d.Height() != 2 {
+		t.Errorf("Height() = %d, expected 2", d.Height())
+	}
+	if d.Spacing() != 1 {
+		t.Errorf("Spacing() = %d, expected 1", d.Spacing())
+	}
+}
+
+func TestRenderEnvironmentBadge(t *testing.T) {
+	d := itemDelegate{environments: config.NewEnvironmentClassifier(config.DefaultEnvironmentRules())}
+
+	badge := d.renderEnvironmentBadge("tenant-prod-01.cumulocity.com")
+	if !strings.Contains(badge, "prod") {
+		t.Errorf("renderEnvironmentBadge() = %q, expected it to contain 'prod'", badge)
+	}
+
+	if d.renderEnvironmentBadge("unclassified.example.com") != "" {
+		t.Errorf("expected no badge for unclassified host")
+	}
+}
+
+func TestRenderMetadataLine(t *testing.T) {
+	d := itemDelegate{}
+
+	session := &core.CumulocitySession{
+		Tenant:   "t12345",
+		Username: "jdoe",
+		Tags:     []string{"c8y", "prod"},
+	}
+
+	line := d.renderMetadataLine(session)
+	for _, want := range []string{"Tenant=t12345", "User=jdoe", "c8y", "prod"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("renderMetadataLine() = %q, expected it to contain %q", line, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package picker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// DefaultColumns is the column set Dump renders for "table"/"tsv" when no
+// columns are given, matching the interactive picker's own description
+// fields.
+var DefaultColumns = []string{"host", "username", "vault", "tags"}
+
+// Dump writes sessions to w as "table" (tab-aligned, for a human) or "tsv"
+// (single tab-separated, for scripts), without launching the Bubble Tea
+// interactive picker -- the non-interactive sibling of Pick, for piping a
+// session listing into jq/cut/a spreadsheet instead of picking one. columns
+// selects which session fields to render, in order; nil/empty falls back to
+// DefaultColumns. See pkg/core/output for "json"/"yaml" dumps, which retain
+// the query metadata Dump's plain rows don't carry.
+func Dump(w io.Writer, sessions []*core.CumulocitySession, format string, columns []string) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+
+	switch format {
+	case "", "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		if err := writeDelimited(tw, sessions, columns, "\t"); err != nil {
+			return err
+		}
+		return tw.Flush()
+	case "tsv":
+		return writeDelimited(w, sessions, columns, "\t")
+	default:
+		return fmt.Errorf("picker: unsupported dump format %q (supported: table, tsv)", format)
+	}
+}
+
+func writeDelimited(w io.Writer, sessions []*core.CumulocitySession, columns []string, sep string) error {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, sep)); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = dumpColumn(session, c)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, sep)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpColumn renders one session field, by lowercase column name, as plain
+// text; an unrecognized column renders as empty rather than erroring, so a
+// typo in --columns degrades gracefully instead of failing the whole dump.
+func dumpColumn(session *core.CumulocitySession, column string) string {
+	switch strings.ToLower(column) {
+	case "name":
+		return session.Name
+	case "host":
+		return core.NormalizeDisplayURL(session.Host)
+	case "username":
+		return session.Username
+	case "tenant":
+		return session.Tenant
+	case "vault":
+		return session.VaultName
+	case "tags":
+		return strings.Join(session.Tags, ",")
+	case "environment":
+		return session.Environment
+	case "uri":
+		return session.SessionURI
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,68 @@
+package picker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func TestDump_DefaultColumns(t *testing.T) {
+	sessions := []*core.CumulocitySession{
+		{Host: "https://prod.example.com", Username: "alice", VaultName: "Employee", Tags: []string{"c8y", "prod"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Dump(&buf, sessions, "tsv", nil); err != nil {
+		t.Fatalf("Dump() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and one row, got %d lines: %q", len(lines), buf.String())
+	}
+	if lines[0] != "HOST\tUSERNAME\tVAULT\tTAGS" {
+		t.Errorf("header = %q, want default column header", lines[0])
+	}
+	if want := "prod.example.com\talice\tEmployee\tc8y,prod"; lines[1] != want {
+		t.Errorf("row = %q, want %q", lines[1], want)
+	}
+}
+
+func TestDump_CustomColumns(t *testing.T) {
+	sessions := []*core.CumulocitySession{
+		{Name: "my-session", Host: "https://prod.example.com", Tenant: "t12345"},
+	}
+
+	var buf bytes.Buffer
+	if err := Dump(&buf, sessions, "tsv", []string{"name", "tenant"}); err != nil {
+		t.Fatalf("Dump() error: %v", err)
+	}
+
+	want := "NAME\tTENANT\nmy-session\tt12345\n"
+	if buf.String() != want {
+		t.Errorf("Dump() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDump_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Dump(&buf, nil, "json", nil); err == nil {
+		t.Error("Dump() with format \"json\" should error (see pkg/core/output for that)")
+	}
+}
+
+func TestDump_TableIsAligned(t *testing.T) {
+	sessions := []*core.CumulocitySession{
+		{Host: "https://a.example.com", Username: "x", VaultName: "v", Tags: nil},
+	}
+
+	var buf bytes.Buffer
+	if err := Dump(&buf, sessions, "table", []string{"host"}); err != nil {
+		t.Fatalf("Dump() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "HOST") {
+		t.Errorf("table dump missing header: %q", buf.String())
+	}
+}
@@ -0,0 +1,65 @@
+package picker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// newListFilter returns the list.FilterFunc the session list's live "/"
+// filter should use for the given PickerMetadata.FilterMode: core.FuzzyScore
+// ranking by default (mode "" or "fuzzy"), or a plain substring match for
+// mode "substring". Both replace bubbles' own sahilm/fuzzy-backed
+// list.DefaultFilter so the live filter ranks results the same way
+// core.FilterSessions does for non-interactive output.
+func newListFilter(mode string) list.FilterFunc {
+	if mode == core.FilterModeSubstring {
+		return substringFilter
+	}
+	return fuzzyFilter
+}
+
+// fuzzyFilter scores every target against term with core.FuzzyScore,
+// keeping only positive scores and sorting the rest descending (ties
+// broken by original order via sort.SliceStable).
+func fuzzyFilter(term string, targets []string) []list.Rank {
+	type scored struct {
+		rank  list.Rank
+		score int
+	}
+
+	candidates := make([]scored, 0, len(targets))
+	for i, target := range targets {
+		score, ok := core.FuzzyScore(term, target)
+		if !ok || score <= 0 {
+			continue
+		}
+		candidates = append(candidates, scored{rank: list.Rank{Index: i}, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	ranks := make([]list.Rank, len(candidates))
+	for i, c := range candidates {
+		ranks[i] = c.rank
+	}
+	return ranks
+}
+
+// substringFilter keeps the tool's original plain case-insensitive
+// substring match, for anyone who needs the filter's legacy behavior
+// instead of ranked relevance.
+func substringFilter(term string, targets []string) []list.Rank {
+	term = strings.ToLower(term)
+	var ranks []list.Rank
+	for i, target := range targets {
+		if strings.Contains(strings.ToLower(target), term) {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+	}
+	return ranks
+}
@@ -1,6 +1,7 @@
 package picker
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/prefs"
 )
 
 // isInCommandSubstitution detects if we're running in command substitution context
@@ -62,6 +64,23 @@ func getTerminalColorProfile() termenv.Profile {
 	return profile
 }
 
+// ErrNotInteractive is returned by PickResult (and Pick/PickWithSelection)
+// when stdin isn't a terminal, so the picker has nothing to render against.
+// This happens when the CLI is driven non-interactively, e.g. round-tripped
+// through a serve daemon (see cmd's --socket flag) from a script or editor
+// plugin -- callers should fall back to a non-interactive presentation
+// instead of propagating this as a hard failure.
+var ErrNotInteractive = errors.New("picker: stdin is not an interactive terminal")
+
+// IsInteractive reports whether stdin looks like an interactive terminal.
+func IsInteractive() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
 func isInCommandSubstitution() bool {
 	// Check if stdout is not a terminal (common in command substitution)
 	stat, err := os.Stdout.Stat()
@@ -85,12 +104,15 @@ func isInCommandSubstitution() bool {
 	return false
 }
 
-// PickerMetadata holds information about the query parameters used
+// PickerMetadata holds information about the query parameters used. It is
+// also serialized verbatim into non-interactive list output (see
+// pkg/core/output), hence the json/yaml tags.
 type PickerMetadata struct {
-	Vaults  []string
-	Tags    []string
-	Filter  string
-	NoColor bool
+	Vaults     []string `json:"vaults,omitempty" yaml:"vaults,omitempty"`
+	Tags       []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Filter     string   `json:"filter,omitempty" yaml:"filter,omitempty"`
+	FilterMode string   `json:"filterMode,omitempty" yaml:"filterMode,omitempty"`
+	NoColor    bool     `json:"noColor,omitempty" yaml:"noColor,omitempty"`
 }
 
 var (
@@ -117,17 +139,22 @@ type listKeyMap struct {
 	toggleHelpMenu   key.Binding
 	insertItem       key.Binding
 	selectItem       key.Binding
+	cycleSort        key.Binding
 }
 
 func newListKeyMap() *listKeyMap {
 	return &listKeyMap{
 		insertItem: key.NewBinding(
-			key.WithKeys("a"),
-			key.WithHelp("a", "add item"),
+			key.WithKeys("i"),
+			key.WithHelp("i", "add item"),
 		),
 		toggleSpinner: key.NewBinding(
+			key.WithKeys("`"),
+			key.WithHelp("`", "toggle spinner"),
+		),
+		cycleSort: key.NewBinding(
 			key.WithKeys("s"),
-			key.WithHelp("s", "toggle spinner"),
+			key.WithHelp("s", "cycle sort"),
 		),
 		toggleTitleBar: key.NewBinding(
 			key.WithKeys("T"),
@@ -159,23 +186,47 @@ type model struct {
 	delegateKeys  *delegateKeyMap
 	wasSelected   bool
 	metadata      PickerMetadata
+	selection     *selectionState
+	prefs         *prefs.Prefs
+
+	// Action panel state. When showActions is true, the action list is
+	// rendered instead of the session list, scoped to actionTarget.
+	showActions  bool
+	actionList   list.Model
+	actionTarget *core.CumulocitySession
+	chosenAction string
 }
 
 func newModel(itemGenerator randomItemGenerator, metadata PickerMetadata) model {
 	var (
 		delegateKeys = newDelegateKeyMap()
 		listKeys     = newListKeyMap()
+		selection    = newSelectionState()
+		userPrefs    = prefs.Load()
 	)
 
-	// Make initial list of items
-	items := make([]list.Item, itemGenerator.Len())
+	// Make initial list of items, ordered per the remembered sort order, with
+	// favorites/last-used taken into account.
+	sessions := make([]*core.CumulocitySession, itemGenerator.Len())
 	for i := 0; i < itemGenerator.Len(); i++ {
-		items[i] = itemGenerator.Next()
+		sessions[i] = itemGenerator.Next()
+	}
+	sortSessions(sessions, userPrefs.SortOrder, userPrefs)
+
+	items := make([]list.Item, len(sessions))
+	preselect := -1
+	for i, session := range sessions {
+		items[i] = session
+		if !userPrefs.LastUsedTime(session).IsZero() &&
+			(preselect == -1 || userPrefs.LastUsedTime(session).After(userPrefs.LastUsedTime(sessions[preselect]))) {
+			preselect = i
+		}
 	}
 
 	// Setup list
-	delegate := newItemDelegate(delegateKeys)
+	delegate := newItemDelegate(delegateKeys, selection, userPrefs)
 	sessionList := list.New(items, delegate, 0, 0)
+	sessionList.Filter = newListFilter(metadata.FilterMode)
 
 	// Build title with metadata information
 	title := buildTitle(itemGenerator.Len(), metadata)
@@ -185,10 +236,21 @@ func newModel(itemGenerator randomItemGenerator, metadata PickerMetadata) model
 	// Hide the status bar by default (which shows "X items")
 	sessionList.SetShowStatusBar(false)
 
+	// Remember the last filter query so the user can recall it with "/".
+	if userPrefs.LastQuery != "" {
+		sessionList.FilterInput.SetValue(userPrefs.LastQuery)
+	}
+
+	// Preselect the most recently used session, if any.
+	if preselect >= 0 {
+		sessionList.Select(preselect)
+	}
+
 	sessionList.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.toggleSpinner,
 			listKeys.insertItem,
+			listKeys.cycleSort,
 			listKeys.toggleTitleBar,
 			listKeys.toggleStatusBar,
 			listKeys.togglePagination,
@@ -199,10 +261,13 @@ func newModel(itemGenerator randomItemGenerator, metadata PickerMetadata) model
 
 	return model{
 		list:          sessionList,
+		actionList:    list.New(nil, newActionDelegate(), 0, 0),
 		keys:          listKeys,
 		delegateKeys:  delegateKeys,
 		itemGenerator: &itemGenerator,
 		metadata:      metadata,
+		selection:     selection,
+		prefs:         userPrefs,
 	}
 }
 
@@ -210,6 +275,18 @@ func (m model) WasSelected() bool {
 	return m.wasSelected
 }
 
+// Selected returns the sessions toggled on via the picker's multi-select
+// bindings (space/a/A), independent of the single highlighted/chosen item.
+func (m model) Selected() []*core.CumulocitySession {
+	return m.selection.Sessions()
+}
+
+// Action returns the action chosen from the per-item action panel (one of
+// the Action* constants), or "" if the panel was never used.
+func (m model) Action() string {
+	return m.chosenAction
+}
+
 func (m model) Init() tea.Cmd {
 	var profile termenv.Profile
 
@@ -239,8 +316,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		h, v := appStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
+		m.actionList.SetSize(msg.Width-h, msg.Height-v)
 
 	case tea.KeyMsg:
+		if m.showActions {
+			return m.updateActions(msg)
+		}
+
 		// Don't match any of the keys below if we're actively filtering.
 		if m.list.FilterState() == list.Filtering {
 			break
@@ -270,6 +352,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.list.SetShowHelp(!m.list.ShowHelp())
 			return m, nil
 
+		case key.Matches(msg, m.keys.cycleSort):
+			m.prefs.SortOrder = prefs.NextSortOrder(m.prefs.SortOrder)
+			sessions := sessionsFromItems(m.list.Items())
+			sortSessions(sessions, m.prefs.SortOrder, m.prefs)
+			items := make([]list.Item, len(sessions))
+			for i, session := range sessions {
+				items[i] = session
+			}
+			cmd := m.list.SetItems(items)
+			_ = m.prefs.Save()
+			statusCmd := m.list.NewStatusMessage(statusMessageStyle("Sort: " + m.prefs.SortOrder))
+			return m, tea.Batch(cmd, statusCmd)
+
 		case key.Matches(msg, m.keys.insertItem):
 			m.delegateKeys.remove.SetEnabled(true)
 			newItem := m.itemGenerator.Next()
@@ -277,7 +372,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			statusCmd := m.list.NewStatusMessage(statusMessageStyle("Added " + newItem.Title()))
 			return m, tea.Batch(insCmd, statusCmd)
 
+		case key.Matches(msg, m.delegateKeys.choose), key.Matches(msg, m.delegateKeys.action):
+			if session, ok := m.list.SelectedItem().(*core.CumulocitySession); ok {
+				m.actionTarget = session
+				m.actionList = newActionList(session)
+				m.actionList.SetSize(m.list.Width(), m.list.Height())
+				m.showActions = true
+			}
+			return m, nil
+
 		case key.Matches(msg, m.keys.selectItem):
+			if session, ok := m.list.SelectedItem().(*core.CumulocitySession); ok {
+				m.bumpLastUsed(session)
+			}
 			m.wasSelected = true
 			return m, tea.Quit
 		}
@@ -291,11 +398,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// bumpLastUsed records session as last used and remembers the current
+// filter query, persisting both so the next invocation opens with this
+// session preselected.
+func (m model) bumpLastUsed(session *core.CumulocitySession) {
+	m.prefs.BumpLastUsed(session)
+	m.prefs.LastQuery = m.list.FilterInput.Value()
+	_ = m.prefs.Save()
+}
+
+// updateActions handles key events while the per-item action panel is open.
+func (m model) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.actionList.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, actionKeys.back):
+			m.showActions = false
+			return m, nil
+
+		case key.Matches(msg, actionKeys.choose):
+			if action, ok := selectedAction(m.actionList); ok {
+				m.chosenAction = action.id
+				if m.actionTarget != nil {
+					m.bumpLastUsed(m.actionTarget)
+				}
+				m.wasSelected = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+	}
+
+	newActionList, cmd := m.actionList.Update(msg)
+	m.actionList = newActionList
+	return m, cmd
+}
+
 func (m model) View() string {
+	if m.showActions {
+		return appStyle.Render(m.actionList.View())
+	}
 	return appStyle.Render(m.list.View())
 }
 
-func Pick(sessions []*core.CumulocitySession, metadata PickerMetadata) (*core.CumulocitySession, error) {
+func runPicker(sessions []*core.CumulocitySession, metadata PickerMetadata) (model, error) {
 	itemGenerator := randomItemGenerator{
 		sessions: sessions,
 	}
@@ -305,11 +450,57 @@ func Pick(sessions []*core.CumulocitySession, metadata PickerMetadata) (*core.Cu
 		os.Exit(1)
 	}
 
-	session := m.(model)
+	return m.(model), nil
+}
+
+// Result is what the picker returns once the user makes a choice: the
+// highlighted session, any action chosen from its action panel, and the set
+// of sessions toggled on via multi-select.
+type Result struct {
+	Session  *core.CumulocitySession
+	Action   string
+	Selected []*core.CumulocitySession
+}
+
+func Pick(sessions []*core.CumulocitySession, metadata PickerMetadata) (*core.CumulocitySession, error) {
+	result, err := PickResult(sessions, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return result.Session, nil
+}
+
+// PickWithSelection runs the picker like Pick, but also returns the set of
+// sessions toggled on via the multi-select bindings (space/a/A), so callers
+// can perform a bulk action (delete, export, open in parallel) in addition
+// to the single chosen session.
+func PickWithSelection(sessions []*core.CumulocitySession, metadata PickerMetadata) (*core.CumulocitySession, []*core.CumulocitySession, error) {
+	result, err := PickResult(sessions, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Session, result.Selected, nil
+}
+
+// PickResult runs the picker and returns the full Result, including the
+// action chosen from the per-item action panel (if any).
+func PickResult(sessions []*core.CumulocitySession, metadata PickerMetadata) (*Result, error) {
+	if !IsInteractive() {
+		return nil, ErrNotInteractive
+	}
+
+	session, err := runPicker(sessions, metadata)
+	if err != nil {
+		return nil, err
+	}
 
 	if session.WasSelected() {
 		if selectedSession, ok := session.list.SelectedItem().(*core.CumulocitySession); ok {
-			return selectedSession, nil
+			return &Result{
+				Session:  selectedSession,
+				Action:   session.Action(),
+				Selected: session.Selected(),
+			}, nil
 		}
 	}
 
@@ -1,6 +1,7 @@
 package picker
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
@@ -309,6 +310,20 @@ func TestPickerMetadataNoColor(t *testing.T) {
 	}
 }
 
+func TestPickResultNotInteractive(t *testing.T) {
+	// go test's stdin is never an interactive terminal, so PickResult
+	// should bail out with ErrNotInteractive rather than trying to launch
+	// the Bubble Tea program.
+	if IsInteractive() {
+		t.Skip("stdin is a terminal in this environment, can't exercise the non-interactive path")
+	}
+
+	_, err := PickResult([]*core.CumulocitySession{{Host: "https://test.cumulocity.com"}}, PickerMetadata{})
+	if !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("PickResult() error = %v, expected ErrNotInteractive", err)
+	}
+}
+
 func TestBuildTitleWithNoColorMetadata(t *testing.T) {
 	// Test that buildTitle works correctly regardless of NoColor flag
 	metadata := PickerMetadata{
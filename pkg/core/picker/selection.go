@@ -0,0 +1,64 @@
+package picker
+
+import (
+	"fmt"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// selectionState tracks the set of sessions toggled on in the picker for
+// batch operations (bulk delete, bulk export, open-in-parallel, ...).
+type selectionState struct {
+	selected map[string]*core.CumulocitySession
+}
+
+func newSelectionState() *selectionState {
+	return &selectionState{selected: make(map[string]*core.CumulocitySession)}
+}
+
+// key combines the session's SessionURI with its Host, since the sessions
+// built from one multi-URL item share a SessionURI and differ only by Host.
+func (s *selectionState) key(session *core.CumulocitySession) string {
+	return session.SessionURI + "|" + session.Host
+}
+
+func (s *selectionState) IsSelected(session *core.CumulocitySession) bool {
+	_, ok := s.selected[s.key(session)]
+	return ok
+}
+
+func (s *selectionState) Select(session *core.CumulocitySession) {
+	s.selected[s.key(session)] = session
+}
+
+func (s *selectionState) Toggle(session *core.CumulocitySession) {
+	if s.IsSelected(session) {
+		delete(s.selected, s.key(session))
+		return
+	}
+	s.Select(session)
+}
+
+func (s *selectionState) Clear() {
+	s.selected = make(map[string]*core.CumulocitySession)
+}
+
+func (s *selectionState) Len() int {
+	return len(s.selected)
+}
+
+// Sessions returns the selected sessions in no particular order.
+func (s *selectionState) Sessions() []*core.CumulocitySession {
+	sessions := make([]*core.CumulocitySession, 0, len(s.selected))
+	for _, session := range s.selected {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func (s *selectionState) StatusText() string {
+	if len(s.selected) == 0 {
+		return "Selection cleared"
+	}
+	return fmt.Sprintf("%d session(s) selected", len(s.selected))
+}
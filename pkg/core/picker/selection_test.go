@@ -0,0 +1,59 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func TestSelectionStateToggle(t *testing.T) {
+	session := &core.CumulocitySession{SessionURI: "op://Employee/item1", Host: "https://test.cumulocity.com"}
+
+	s := newSelectionState()
+	if s.IsSelected(session) {
+		t.Fatal("expected session to start unselected")
+	}
+
+	s.Toggle(session)
+	if !s.IsSelected(session) {
+		t.Fatal("expected session to be selected after toggle")
+	}
+
+	s.Toggle(session)
+	if s.IsSelected(session) {
+		t.Fatal("expected session to be unselected after second toggle")
+	}
+}
+
+func TestSelectionStateSelectAllAndClear(t *testing.T) {
+	sessions := []*core.CumulocitySession{
+		{SessionURI: "op://Employee/item1", Host: "https://a.cumulocity.com"},
+		{SessionURI: "op://Employee/item2", Host: "https://b.cumulocity.com"},
+	}
+
+	s := newSelectionState()
+	for _, session := range sessions {
+		s.Select(session)
+	}
+
+	if s.Len() != 2 {
+		t.Fatalf("expected 2 selected sessions, got %d", s.Len())
+	}
+
+	s.Clear()
+	if s.Len() != 0 {
+		t.Fatalf("expected 0 selected sessions after clear, got %d", s.Len())
+	}
+}
+
+func TestSelectionStateStatusText(t *testing.T) {
+	s := newSelectionState()
+	if got := s.StatusText(); got != "Selection cleared" {
+		t.Errorf("StatusText() = %q, expected empty-selection message", got)
+	}
+
+	s.Select(&core.CumulocitySession{SessionURI: "op://Employee/item1"})
+	if got := s.StatusText(); got != "1 session(s) selected" {
+		t.Errorf("StatusText() = %q, expected 1 session(s) selected", got)
+	}
+}
@@ -0,0 +1,42 @@
+package picker
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/prefs"
+)
+
+// sortSessions orders sessions in place according to order (one of the
+// prefs.SortBy* constants), falling back to host order as the tiebreaker so
+// the result is always stable and deterministic.
+func sortSessions(sessions []*core.CumulocitySession, order string, p *prefs.Prefs) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		switch order {
+		case prefs.SortByFavorite:
+			fi, fj := p.IsFavorite(sessions[i]), p.IsFavorite(sessions[j])
+			if fi != fj {
+				return fi
+			}
+		case prefs.SortByLastUsed:
+			ti, tj := p.LastUsedTime(sessions[i]), p.LastUsedTime(sessions[j])
+			if !ti.Equal(tj) {
+				return ti.After(tj)
+			}
+		}
+		return core.NormalizeDisplayURL(sessions[i].Host) < core.NormalizeDisplayURL(sessions[j].Host)
+	})
+}
+
+// sessionsFromItems extracts the sessions underlying a list's items,
+// discarding anything that isn't a *core.CumulocitySession.
+func sessionsFromItems(items []list.Item) []*core.CumulocitySession {
+	sessions := make([]*core.CumulocitySession, 0, len(items))
+	for _, item := range items {
+		if session, ok := item.(*core.CumulocitySession); ok {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
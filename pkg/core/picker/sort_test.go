@@ -0,0 +1,36 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/prefs"
+)
+
+func TestSortSessionsByHost(t *testing.T) {
+	sessions := []*core.CumulocitySession{
+		{Host: "https://b.cumulocity.com"},
+		{Host: "https://a.cumulocity.com"},
+	}
+
+	sortSessions(sessions, prefs.SortByHost, &prefs.Prefs{})
+
+	if sessions[0].Host != "https://a.cumulocity.com" {
+		t.Errorf("expected a.cumulocity.com first, got %s", sessions[0].Host)
+	}
+}
+
+func TestSortSessionsByFavorite(t *testing.T) {
+	favored := &core.CumulocitySession{ItemID: "fav", Host: "https://z.cumulocity.com"}
+	other := &core.CumulocitySession{ItemID: "other", Host: "https://a.cumulocity.com"}
+	sessions := []*core.CumulocitySession{other, favored}
+
+	p := &prefs.Prefs{}
+	p.ToggleFavorite(favored)
+
+	sortSessions(sessions, prefs.SortByFavorite, p)
+
+	if sessions[0] != favored {
+		t.Errorf("expected favorited session first, got host %s", sessions[0].Host)
+	}
+}
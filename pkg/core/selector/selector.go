@@ -0,0 +1,347 @@
+// Package selector parses a Kubernetes-style label selector expression and
+// compiles it into a predicate over a flat set of 1Password tags. Unlike
+// Kubernetes labels, 1Password tags have no inherent key/value structure, so
+// "key=value" terms match a tag literally equal to "key=value" as well as a
+// tag with prefix "key:" and a value matching "value" (both are conventions
+// users already use in 1Password).
+package selector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Selector is a compiled label-selector expression. Construct one with
+// Parse; the zero value matches everything.
+type Selector struct {
+	groups   []group
+	legacyOr bool
+	raw      string
+}
+
+type group struct {
+	terms []term
+}
+
+type termKind int
+
+const (
+	kindExists termKind = iota
+	kindNotExists
+	kindEquals
+	kindNotEquals
+	kindIn
+	kindNotIn
+)
+
+type term struct {
+	kind   termKind
+	key    string
+	value  string
+	values []string
+}
+
+// Parse compiles a selector expression. Commas separate AND-ed terms within
+// a group; pipes ("|") separate OR-ed groups. Supported terms:
+//
+//	key              tag "key" is present
+//	!key             tag "key" is absent
+//	key=value        tag equals "key=value" or "key:value" (glob allowed in value)
+//	key!=value       negation of the above
+//	key in (a,b)     tag matches key=a or key=b
+//	key notin (a,b)  negation of the above
+//
+// As a special case, an expression made up entirely of bare identifiers
+// (e.g. "c8y,production") matches if ANY of them is present, preserving
+// this tool's historical comma-separated-OR behavior for plain tag lists.
+func Parse(raw string) (*Selector, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return &Selector{raw: raw}, nil
+	}
+
+	groupStrs, err := splitTopLevel(trimmed, '|')
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]group, 0, len(groupStrs))
+	for _, gs := range groupStrs {
+		g, err := parseGroup(gs)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	sel := &Selector{groups: groups, raw: raw}
+	if len(groups) == 1 && allBare(groups[0]) {
+		sel.legacyOr = true
+	}
+	return sel, nil
+}
+
+func allBare(g group) bool {
+	for _, t := range g.terms {
+		if t.kind != kindExists {
+			return false
+		}
+	}
+	return true
+}
+
+func parseGroup(s string) (group, error) {
+	termStrs, err := splitTopLevel(s, ',')
+	if err != nil {
+		return group{}, err
+	}
+
+	terms := make([]term, 0, len(termStrs))
+	for _, ts := range termStrs {
+		t, err := parseTerm(ts)
+		if err != nil {
+			return group{}, err
+		}
+		terms = append(terms, t)
+	}
+	return group{terms: terms}, nil
+}
+
+func parseTerm(s string) (term, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return term{}, fmt.Errorf("selector: empty term")
+	}
+
+	if strings.HasPrefix(s, "!") {
+		key := strings.TrimSpace(s[1:])
+		if key == "" {
+			return term{}, fmt.Errorf("selector: empty key in %q", s)
+		}
+		return term{kind: kindNotExists, key: key}, nil
+	}
+
+	if key, values, ok, err := parseSetTerm(s, " in ("); ok || err != nil {
+		if err != nil {
+			return term{}, err
+		}
+		return term{kind: kindIn, key: key, values: values}, nil
+	}
+
+	if key, values, ok, err := parseSetTerm(s, " notin ("); ok || err != nil {
+		if err != nil {
+			return term{}, err
+		}
+		return term{kind: kindNotIn, key: key, values: values}, nil
+	}
+
+	if idx := strings.Index(s, "!="); idx >= 0 {
+		key := strings.TrimSpace(s[:idx])
+		value := strings.TrimSpace(s[idx+2:])
+		if key == "" || value == "" {
+			return term{}, fmt.Errorf("selector: malformed term %q", s)
+		}
+		return term{kind: kindNotEquals, key: key, value: value}, nil
+	}
+
+	if idx := strings.Index(s, "="); idx >= 0 {
+		key := strings.TrimSpace(s[:idx])
+		value := strings.TrimSpace(s[idx+1:])
+		if key == "" || value == "" {
+			return term{}, fmt.Errorf("selector: malformed term %q", s)
+		}
+		return term{kind: kindEquals, key: key, value: value}, nil
+	}
+
+	return term{kind: kindExists, key: s}, nil
+}
+
+// parseSetTerm recognizes "key<op>(v1,v2)" terms such as "env in (a,b)".
+// ok is false (with a nil error) when s doesn't use this operator at all, so
+// callers can try the next operator in turn.
+func parseSetTerm(s, op string) (key string, values []string, ok bool, err error) {
+	idx := strings.Index(s, op)
+	if idx < 0 {
+		return "", nil, false, nil
+	}
+	if !strings.HasSuffix(s, ")") {
+		return "", nil, false, fmt.Errorf("selector: unterminated %q in %q", strings.TrimSpace(op), s)
+	}
+
+	key = strings.TrimSpace(s[:idx])
+	inner := s[idx+len(op) : len(s)-1]
+	if key == "" {
+		return "", nil, false, fmt.Errorf("selector: empty key in %q", s)
+	}
+
+	parts, err := splitTopLevel(inner, ',')
+	if err != nil {
+		return "", nil, false, err
+	}
+	values = make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			return "", nil, false, fmt.Errorf("selector: empty value in %q", s)
+		}
+		values = append(values, p)
+	}
+	return key, values, true, nil
+}
+
+// splitTopLevel splits s on sep, ignoring occurrences inside parentheses,
+// and trims whitespace from each piece.
+func splitTopLevel(s string, sep rune) ([]string, error) {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("selector: unbalanced parentheses in %q", s)
+			}
+		case sep:
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("selector: unbalanced parentheses in %q", s)
+	}
+	parts = append(parts, strings.TrimSpace(s[start:]))
+
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			result = append(result, p)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("selector: empty expression in %q", s)
+	}
+	return result, nil
+}
+
+// Matches reports whether tags satisfies the selector. A Selector with no
+// groups (the zero value, or one parsed from an empty/whitespace string)
+// matches everything.
+func (s *Selector) Matches(tags []string) bool {
+	if s == nil || len(s.groups) == 0 {
+		return true
+	}
+
+	if s.legacyOr {
+		for _, t := range s.groups[0].terms {
+			if t.matches(tags) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, g := range s.groups {
+		if g.matches(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g group) matches(tags []string) bool {
+	for _, t := range g.terms {
+		if !t.matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t term) matches(tags []string) bool {
+	switch t.kind {
+	case kindExists:
+		return hasTag(tags, t.key)
+	case kindNotExists:
+		return !hasTag(tags, t.key)
+	case kindEquals:
+		return matchKeyValue(tags, t.key, t.value)
+	case kindNotEquals:
+		return !matchKeyValue(tags, t.key, t.value)
+	case kindIn:
+		for _, v := range t.values {
+			if matchKeyValue(tags, t.key, v) {
+				return true
+			}
+		}
+		return false
+	case kindNotIn:
+		for _, v := range t.values {
+			if matchKeyValue(tags, t.key, v) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func hasTag(tags []string, name string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKeyValue matches a flat tag against "key=value", honoring both the
+// "key=value" and "key:value" 1Password tag conventions. value may contain
+// a path.Match glob pattern (e.g. "prod-*").
+func matchKeyValue(tags []string, key, value string) bool {
+	literal := key + "=" + value
+	for _, tag := range tags {
+		if strings.EqualFold(tag, literal) {
+			return true
+		}
+		if ok, _ := path.Match(literal, tag); ok {
+			return true
+		}
+		if rest, ok := cutPrefixFold(tag, key+":"); ok {
+			if matched, _ := path.Match(value, rest); matched || strings.EqualFold(rest, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
+
+// IsLegacyBareList reports whether the selector is the legacy
+// comma-separated-OR form (only bare identifiers, no operators), which is
+// the only form with a direct op CLI "--tags" equivalent.
+func (s *Selector) IsLegacyBareList() bool {
+	return s != nil && s.legacyOr
+}
+
+// String returns the expression the selector was parsed from, with
+// surrounding whitespace trimmed, suitable for display (e.g. in the picker's
+// query summary).
+func (s *Selector) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(s.raw)
+}
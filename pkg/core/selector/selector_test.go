@@ -0,0 +1,162 @@
+package selector
+
+import "testing"
+
+func TestParseEmptyMatchesEverything(t *testing.T) {
+	sel, err := Parse("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches(nil) {
+		t.Errorf("expected empty selector to match a nil tag set")
+	}
+	if !sel.Matches([]string{"anything"}) {
+		t.Errorf("expected empty selector to match any tag set")
+	}
+
+	sel, err = Parse("   ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"anything"}) {
+		t.Errorf("expected whitespace-only selector to match any tag set")
+	}
+}
+
+func TestParseBareCommaListIsOR(t *testing.T) {
+	sel, err := Parse("c8y, production")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"production"}) {
+		t.Errorf("expected OR match on 'production'")
+	}
+	if !sel.Matches([]string{"c8y"}) {
+		t.Errorf("expected OR match on 'c8y'")
+	}
+	if sel.Matches([]string{"other"}) {
+		t.Errorf("expected no match for unrelated tag")
+	}
+}
+
+func TestParseNegation(t *testing.T) {
+	sel, err := Parse("c8y,!deprecated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"c8y"}) {
+		t.Errorf("expected match when c8y present and deprecated absent")
+	}
+	if sel.Matches([]string{"c8y", "deprecated"}) {
+		t.Errorf("expected no match when deprecated is present")
+	}
+}
+
+func TestParseKeyValueConventions(t *testing.T) {
+	sel, err := Parse("env=prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"env=prod"}) {
+		t.Errorf("expected literal env=prod tag to match")
+	}
+	if !sel.Matches([]string{"env:prod"}) {
+		t.Errorf("expected env:prod tag to match the env=prod selector")
+	}
+	if sel.Matches([]string{"env=staging"}) {
+		t.Errorf("expected env=staging to not match")
+	}
+}
+
+func TestParseKeyValueGlob(t *testing.T) {
+	sel, err := Parse("env=prod-*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"env:prod-01"}) {
+		t.Errorf("expected glob to match env:prod-01")
+	}
+	if sel.Matches([]string{"env:staging-01"}) {
+		t.Errorf("expected glob to not match env:staging-01")
+	}
+}
+
+func TestParseNotEquals(t *testing.T) {
+	sel, err := Parse("env!=prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sel.Matches([]string{"env=prod"}) {
+		t.Errorf("expected no match for env=prod")
+	}
+	if !sel.Matches([]string{"env=staging"}) {
+		t.Errorf("expected match for env=staging")
+	}
+}
+
+func TestParseInAndNotIn(t *testing.T) {
+	sel, err := Parse("env in (prod,staging)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"env=staging"}) {
+		t.Errorf("expected match for staging")
+	}
+	if sel.Matches([]string{"env=dev"}) {
+		t.Errorf("expected no match for dev")
+	}
+
+	sel, err = Parse("env notin (prod,staging)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sel.Matches([]string{"env=staging"}) {
+		t.Errorf("expected no match for staging")
+	}
+	if !sel.Matches([]string{"env=dev"}) {
+		t.Errorf("expected match for dev")
+	}
+}
+
+func TestParseOrGroupsWithAndPrecedence(t *testing.T) {
+	sel, err := Parse("env=prod,team=sre|env=staging")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Matches([]string{"env=prod", "team=sre"}) {
+		t.Errorf("expected first AND-group to match")
+	}
+	if sel.Matches([]string{"env=prod"}) {
+		t.Errorf("expected first AND-group to require both terms")
+	}
+	if !sel.Matches([]string{"env=staging"}) {
+		t.Errorf("expected second OR-group to match on its own")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"env=",
+		"=prod",
+		"!",
+		"env in (prod",
+		"env in ()",
+		"(unbalanced",
+		",",
+	}
+	for _, c := range cases {
+		if _, err := Parse(c); err == nil {
+			t.Errorf("expected an error parsing %q", c)
+		}
+	}
+}
+
+func TestStringReturnsCanonicalForm(t *testing.T) {
+	sel, err := Parse("env=prod,!deprecated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sel.String(); got != "env=prod,!deprecated" {
+		t.Errorf("expected canonical string %q, got %q", "env=prod,!deprecated", got)
+	}
+}
@@ -2,25 +2,50 @@ package core
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
 type CumulocitySession struct {
-	SessionURI string `json:"sessionUri,omitempty"`
-	Name       string `json:"name,omitempty"`
-	Host       string `json:"host,omitempty"`
-	Username   string `json:"username,omitempty"`
-	Password   string `json:"password,omitempty"`
-	Tenant     string `json:"tenant,omitempty"`
-	TOTP       string `json:"totp,omitempty"`
-	TOTPSecret string `json:"totpSecret,omitempty"`
+	SessionURI string `json:"sessionUri,omitempty" yaml:"sessionUri,omitempty"`
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Host       string `json:"host,omitempty" yaml:"host,omitempty"`
+	Username   string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password   string `json:"password,omitempty" yaml:"password,omitempty"`
+	Tenant     string `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+	TOTP       string `json:"totp,omitempty" yaml:"totp,omitempty"`
+	TOTPSecret string `json:"totpSecret,omitempty" yaml:"totpSecret,omitempty"`
+
+	// Environment is set by a matching URL rule (see SetURLRuleEngine),
+	// classifying the session's URL (e.g. "prod", "staging") beyond what
+	// the 1Password item's own tags and labels capture.
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+
+	// OIDC/OAuth2, populated when the item configures oidc_issuer/
+	// oidc_client_id/oidc_client_secret fields instead of (or in addition
+	// to) a password. TokenType/AccessToken are the bearer token a
+	// go-c8y-cli consumer uses directly; RefreshToken round-trips through
+	// the backing item so later invocations can skip the interactive flow.
+	TokenType        string `json:"tokenType,omitempty" yaml:"tokenType,omitempty"`
+	AccessToken      string `json:"accessToken,omitempty" yaml:"accessToken,omitempty"`
+	OIDCIssuer       string `json:"oidcIssuer,omitempty" yaml:"oidcIssuer,omitempty"`
+	OIDCClientID     string `json:"oidcClientId,omitempty" yaml:"oidcClientId,omitempty"`
+	OIDCClientSecret string `json:"oidcClientSecret,omitempty" yaml:"oidcClientSecret,omitempty"`
+	RefreshToken     string `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
 
 	// 1Password specific
-	ItemID    string   `json:"itemId,omitempty"`
-	ItemName  string   `json:"itemName,omitempty"`
-	VaultID   string   `json:"vaultId,omitempty"`
-	VaultName string   `json:"vaultName,omitempty"`
-	Tags      []string `json:"tags,omitempty"` // Only the matching requested tags
+	ItemID    string   `json:"itemId,omitempty" yaml:"itemId,omitempty"`
+	ItemName  string   `json:"itemName,omitempty" yaml:"itemName,omitempty"`
+	VaultID   string   `json:"vaultId,omitempty" yaml:"vaultId,omitempty"`
+	VaultName string   `json:"vaultName,omitempty" yaml:"vaultName,omitempty"`
+	Tags      []string `json:"tags,omitempty" yaml:"tags,omitempty"` // Only the matching requested tags
+
+	// Score is the relevance score FilterSessions ranked this session with
+	// (see FuzzyScore). It is transient -- not serialized -- and only set
+	// on the copies FilterSessions' fuzzy mode returns, not on the input
+	// sessions it was passed; callers use it to render a relevance
+	// indicator, not to persist or compare across calls.
+	Score int `json:"-" yaml:"-"`
 }
 
 // URLSource represents a URL from any source (URLs array or fields)
@@ -37,6 +62,13 @@ type ItemFields struct {
 	Password   string
 	TOTPSecret string
 	Tenant     string
+
+	// OIDC/OAuth2 fields, present only on items configured for federated
+	// login (oidc_issuer, oidc_client_id, oidc_client_secret, refresh_token).
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	RefreshToken     string
 }
 
 // Item represents a simplified 1Password item for session creation
@@ -85,10 +117,21 @@ func (i CumulocitySession) FilterValue() string {
 }
 
 func (i CumulocitySession) Title() string {
+	if titleTemplate != nil {
+		if rendered, err := renderSessionTemplate(titleTemplate, &i, Item{}, URLSource{}); err == nil {
+			return rendered
+		}
+	}
 	return NormalizeDisplayURL(i.Host)
 }
 
 func (i CumulocitySession) Description() string {
+	if descriptionTemplate != nil {
+		if rendered, err := renderSessionTemplate(descriptionTemplate, &i, Item{}, URLSource{}); err == nil {
+			return rendered
+		}
+	}
+
 	fields := []string{
 		"Username=%s",
 	}
@@ -153,7 +196,14 @@ func BuildSessionName(item Item, urlSource URLSource, urlIndex int, totalURLs in
 }
 
 func BuildSessionURI(vault, item string) string {
-	return fmt.Sprintf("op://%s/%s", vault, item)
+	return BuildSessionURIScheme("op", vault, item)
+}
+
+// BuildSessionURIScheme is like BuildSessionURI but with a caller-supplied
+// scheme, for SecretBackend implementations other than 1Password (e.g. a
+// HashiCorp Vault backend builds "vault://<mount>/<path>" URIs).
+func BuildSessionURIScheme(scheme, vault, item string) string {
+	return fmt.Sprintf("%s://%s/%s", scheme, vault, item)
 }
 
 // CreateSession builds a CumulocitySession from extracted data
@@ -163,7 +213,7 @@ func CreateSession(item Item, fields ItemFields, vaultName string, urlSource URL
 	if useFiltering {
 		tags = filteredTags // Use filtered tags (could be empty)
 	} else {
-		tags = item.Tags // Use all item tags
+		tags = NormalizeTags(item.Tags) // Use all item tags
 	}
 
 	return &CumulocitySession{
@@ -179,6 +229,11 @@ func CreateSession(item Item, fields ItemFields, vaultName string, urlSource URL
 		VaultName:  vaultName,
 		TOTPSecret: fields.TOTPSecret,
 		Tags:       tags,
+
+		OIDCIssuer:       fields.OIDCIssuer,
+		OIDCClientID:     fields.OIDCClientID,
+		OIDCClientSecret: fields.OIDCClientSecret,
+		RefreshToken:     fields.RefreshToken,
 	}
 }
 
@@ -197,12 +252,99 @@ func FilterMatchingTags(itemTags []string, requestedTags []string) []string {
 			}
 		}
 	}
-	return matchingTags
+	return NormalizeTags(matchingTags)
+}
+
+// NormalizeTags lowercases, deduplicates, and lexically sorts tags, so an
+// item listing the same tag twice (or with inconsistent casing) still
+// produces a stable Tags slice -- important for diffing/caching serialized
+// output across runs, and for the picker's title builder, which would
+// otherwise double-count a duplicated tag. Returns nil for an empty/nil
+// input, matching FilterMatchingTags' "no tags" sentinel.
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		lower := strings.ToLower(tag)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		normalized = append(normalized, lower)
+	}
+
+	sort.Strings(normalized)
+	return normalized
 }
 
 // MapToSessions creates one or more sessions from a 1Password item, handling multiple URLs
 // If requestedTags is provided, only matching tags will be included in the sessions
 func MapToSessions(item Item, fields ItemFields, allURLs []URLSource, vaultName string, requestedTags []string) []*CumulocitySession {
+	return MapToSessionsScheme("op", item, fields, allURLs, vaultName, requestedTags)
+}
+
+// URLRuleMatcher classifies a session URL (by href and/or label), set via
+// SetURLRuleEngine. pkg/config's URLRuleEngine implements this, keeping
+// pkg/core itself unaware of config file loading.
+type URLRuleMatcher interface {
+	// Match returns the environment, extra tags, and name a matching rule
+	// assigns for href/label (any of which may be empty even if matched is
+	// true), or matched=false if no rule fired.
+	Match(href, label string) (environment string, tags []string, name string, matched bool)
+}
+
+// urlRules, set via SetURLRuleEngine, is consulted by MapToSessionsScheme
+// for every URL a session is built from.
+var urlRules URLRuleMatcher
+
+// SetURLRuleEngine installs matcher as the URL rule engine every session
+// built afterwards is classified with; nil disables rule-based
+// classification (the default).
+func SetURLRuleEngine(matcher URLRuleMatcher) {
+	urlRules = matcher
+}
+
+// applyURLRule classifies session against urlSource with the active rule
+// engine (a no-op if none is set or none matches): setting Environment,
+// merging in the rule's tags, and overriding Name if the rule names one.
+func applyURLRule(session *CumulocitySession, urlSource URLSource) {
+	if urlRules == nil {
+		return
+	}
+
+	environment, tags, name, matched := urlRules.Match(urlSource.URL, urlSource.Label)
+	if !matched {
+		return
+	}
+
+	session.Environment = environment
+	if name != "" {
+		session.Name = name
+	}
+	for _, tag := range tags {
+		if !containsFold(session.Tags, tag) {
+			session.Tags = append(session.Tags, tag)
+		}
+	}
+}
+
+func containsFold(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if strings.EqualFold(s, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapToSessionsScheme is like MapToSessions, but builds SessionURI values
+// with the given URI scheme instead of always assuming "op" -- used by
+// SecretBackend implementations other than 1Password.
+func MapToSessionsScheme(scheme string, item Item, fields ItemFields, allURLs []URLSource, vaultName string, requestedTags []string) []*CumulocitySession {
 	// Filter tags if requested
 	var filteredTags []string
 	var useFiltering bool
@@ -215,8 +357,10 @@ func MapToSessions(item Item, fields ItemFields, allURLs []URLSource, vaultName
 	if len(allURLs) == 0 {
 		emptyURL := URLSource{URL: "", Label: "", Primary: false, Source: "none"}
 		sessionName := BuildSessionName(item, emptyURL, 0, 1, nil)
-		sessionURI := BuildSessionURI(item.Vault.ID, item.ID)
+		sessionURI := BuildSessionURIScheme(scheme, item.Vault.ID, item.ID)
 		session := CreateSession(item, fields, vaultName, emptyURL, sessionName, sessionURI, filteredTags, useFiltering)
+		applyNameAndURITemplates(session, item, emptyURL)
+		applyURLRule(session, emptyURL)
 		return []*CumulocitySession{session}
 	}
 
@@ -230,8 +374,10 @@ func MapToSessions(item Item, fields ItemFields, allURLs []URLSource, vaultName
 	sessions := make([]*CumulocitySession, 0, len(allURLs))
 	for i, urlSource := range allURLs {
 		sessionName := BuildSessionName(item, urlSource, i, len(allURLs), labelCounts)
-		sessionURI := BuildSessionURI(item.Vault.ID, item.ID)
+		sessionURI := BuildSessionURIScheme(scheme, item.Vault.ID, item.ID)
 		session := CreateSession(item, fields, vaultName, urlSource, sessionName, sessionURI, filteredTags, useFiltering)
+		applyNameAndURITemplates(session, item, urlSource)
+		applyURLRule(session, urlSource)
 		sessions = append(sessions, session)
 	}
 
@@ -271,25 +417,3 @@ func extractHostname(urlStr string) string {
 
 	return hostname
 }
-
-// FilterSessions filters sessions based on a query string that matches against
-// session name, item name, or host URL (case-insensitive)
-func FilterSessions(sessions []*CumulocitySession, filter string) []*CumulocitySession {
-	if filter == "" {
-		return sessions
-	}
-
-	filter = strings.ToLower(filter)
-	var filtered []*CumulocitySession
-
-	for _, session := range sessions {
-		// Check if filter matches any of these fields (case-insensitive)
-		if strings.Contains(strings.ToLower(session.Name), filter) ||
-			strings.Contains(strings.ToLower(session.ItemName), filter) ||
-			strings.Contains(strings.ToLower(session.Host), filter) {
-			filtered = append(filtered, session)
-		}
-	}
-
-	return filtered
-}
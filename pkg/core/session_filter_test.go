@@ -1,8 +1,14 @@
 package core
 
-import (
-	"testing"
-)
+import "testing"
+
+func namesOf(sessions []*CumulocitySession) []string {
+	names := make([]string, len(sessions))
+	for i, session := range sessions {
+		names[i] = session.Name
+	}
+	return names
+}
 
 func TestFilterSessions(t *testing.T) {
 	sessions := []*CumulocitySession{
@@ -32,89 +38,66 @@ func TestFilterSessions(t *testing.T) {
 	tests := []struct {
 		name          string
 		filter        string
-		expectedCount int
 		expectedNames []string
 	}{
 		{
 			name:          "empty filter returns all sessions",
 			filter:        "",
-			expectedCount: 3,
 			expectedNames: []string{"Production Session", "Test Session", "Development Session"},
 		},
 		{
 			name:          "filter by session name",
 			filter:        "prod",
-			expectedCount: 1,
 			expectedNames: []string{"Production Session"},
 		},
 		{
 			name:          "filter by item name",
 			filter:        "testing",
-			expectedCount: 1,
 			expectedNames: []string{"Test Session"},
 		},
 		{
 			name:          "filter by host URL",
 			filter:        "dev.example",
-			expectedCount: 1,
 			expectedNames: []string{"Development Session"},
 		},
 		{
 			name:          "filter by username",
 			filter:        "admin",
-			expectedCount: 1,
 			expectedNames: []string{"Production Session"},
 		},
 		{
 			name:          "filter by username - partial match",
 			filter:        "test",
-			expectedCount: 1,
 			expectedNames: []string{"Test Session"},
 		},
 		{
 			name:          "filter by username - case insensitive",
 			filter:        "DEVELOPER",
-			expectedCount: 1,
 			expectedNames: []string{"Development Session"},
 		},
-		{
-			name:          "filter matches multiple fields",
-			filter:        "test",
-			expectedCount: 1, // Should match both host (test.example.com) and username (testuser)
-			expectedNames: []string{"Test Session"},
-		},
 		{
 			name:          "no matches",
 			filter:        "nonexistent",
-			expectedCount: 0,
 			expectedNames: []string{},
 		},
 		{
 			name:          "case insensitive matching",
 			filter:        "PROD",
-			expectedCount: 1,
 			expectedNames: []string{"Production Session"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FilterSessions(sessions, tt.filter)
+			result := FilterSessions(sessions, tt.filter, FilterModeFuzzy)
 
-			if len(result) != tt.expectedCount {
-				t.Errorf("FilterSessions() returned %d sessions, expected %d",
-					len(result), tt.expectedCount)
+			got := namesOf(result)
+			if len(got) != len(tt.expectedNames) {
+				t.Fatalf("FilterSessions() = %v, expected %v", got, tt.expectedNames)
 			}
-
-			// Check that the expected sessions are in the result
-			for i, expectedName := range tt.expectedNames {
-				if i >= len(result) {
-					t.Errorf("Missing expected session: %s", expectedName)
-					continue
-				}
-				if result[i].Name != expectedName {
-					t.Errorf("Expected session name %s at index %d, got %s",
-						expectedName, i, result[i].Name)
+			for i, name := range tt.expectedNames {
+				if got[i] != name {
+					t.Errorf("FilterSessions()[%d] = %q, expected %q", i, got[i], name)
 				}
 			}
 		})
@@ -163,20 +146,166 @@ func TestFilterSessions_UsernameSpecific(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("username_filter_"+tt.filter, func(t *testing.T) {
-			result := FilterSessions(sessions, tt.filter)
+			result := FilterSessions(sessions, tt.filter, FilterModeFuzzy)
 
-			if len(result) != len(tt.expected) {
-				t.Errorf("Expected %d sessions for filter '%s', got %d",
-					len(tt.expected), tt.filter, len(result))
-				return
+			got := namesOf(result)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v for filter '%s', got %v", tt.expected, tt.filter, got)
 			}
-
-			for i, expectedName := range tt.expected {
-				if result[i].Name != expectedName {
-					t.Errorf("Expected session '%s' at index %d, got '%s'",
-						expectedName, i, result[i].Name)
+			for i, name := range tt.expected {
+				if got[i] != name {
+					t.Errorf("Expected session '%s' at index %d, got '%s'", name, i, got[i])
 				}
 			}
 		})
 	}
 }
+
+func TestFilterSessions_RanksByRelevance(t *testing.T) {
+	sessions := []*CumulocitySession{
+		{Name: "xprod-staging", Host: "https://one.example.com"},
+		{Name: "prod", Host: "https://two.example.com"},
+	}
+
+	result := FilterSessions(sessions, "prod", FilterModeFuzzy)
+	got := namesOf(result)
+	want := []string{"prod", "xprod-staging"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("expected a word-boundary match to outrank a mid-word match, got %v", got)
+	}
+}
+
+func TestFilterSessions_DoesNotMutateInputSessions(t *testing.T) {
+	sessions := []*CumulocitySession{
+		{Name: "prod", Host: "https://one.example.com"},
+	}
+
+	result := FilterSessions(sessions, "prod", FilterModeFuzzy)
+
+	if sessions[0].Score != 0 {
+		t.Errorf("expected the input session to be left unmodified, got Score = %d", sessions[0].Score)
+	}
+	if result[0] == sessions[0] {
+		t.Fatal("expected FilterSessions to return a copy, not the input pointer")
+	}
+	if result[0].Score == 0 {
+		t.Error("expected the returned copy to carry the match score")
+	}
+}
+
+func TestFilterSessions_Unicode(t *testing.T) {
+	sessions := []*CumulocitySession{
+		{Name: "Zürich Tenant", Host: "https://zuerich.example.com"},
+		{Name: "Other Session", Host: "https://other.example.com"},
+	}
+
+	result := FilterSessions(sessions, "zür", FilterModeFuzzy)
+	got := namesOf(result)
+	if len(got) != 1 || got[0] != "Zürich Tenant" {
+		t.Fatalf("expected unicode query to match, got %v", got)
+	}
+}
+
+func TestFilterSessions_EmptyFilterPassesThrough(t *testing.T) {
+	sessions := []*CumulocitySession{{Name: "A"}, {Name: "B"}}
+	result := FilterSessions(sessions, "", FilterModeFuzzy)
+	if len(result) != len(sessions) || result[0] != sessions[0] || result[1] != sessions[1] {
+		t.Fatalf("expected an empty filter to return sessions unfiltered and in order")
+	}
+}
+
+func TestFilterSessions_SubstringModeIsLegacyBehavior(t *testing.T) {
+	sessions := []*CumulocitySession{
+		{Name: "Production Session", ItemName: "Prod Environment", Host: "https://prod.example.com", Username: "admin"},
+	}
+
+	// Substring mode never checked Username, only Name/ItemName/Host -- a
+	// script depending on --filter-mode=substring should keep seeing that.
+	if got := FilterSessions(sessions, "admin", FilterModeSubstring); len(got) != 0 {
+		t.Errorf("expected substring mode to ignore Username, got %v", namesOf(got))
+	}
+	if got := FilterSessions(sessions, "prod", FilterModeSubstring); len(got) != 1 {
+		t.Errorf("expected substring mode to still match Name/ItemName/Host, got %v", namesOf(got))
+	}
+}
+
+func TestFuzzyScore_WordBoundaryBeatsMidWordMatch(t *testing.T) {
+	boundary, ok := FuzzyScore("prod", "prod")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	midWord, ok := FuzzyScore("prod", "xprod")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if boundary <= midWord {
+		t.Errorf("expected a start-of-string match (%d) to outscore a mid-word match (%d)", boundary, midWord)
+	}
+}
+
+func TestFuzzyScore_RejectsMissingRune(t *testing.T) {
+	if _, ok := FuzzyScore("xyz", "abc"); ok {
+		t.Error("expected no match when a query rune never occurs in the candidate")
+	}
+}
+
+func TestFuzzyScore_PenalizesSkippedCharacters(t *testing.T) {
+	tight, ok := FuzzyScore("ab", "ab")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	loose, ok := FuzzyScore("ab", "a........b")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if tight <= loose {
+		t.Errorf("expected fewer skipped characters (%d) to score higher than more (%d)", tight, loose)
+	}
+}
+
+func TestFuzzyMatch_ReturnsMatchedPositions(t *testing.T) {
+	score, positions, ok := FuzzyMatch("pd", "prod")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if want := []int{0, 3}; len(positions) != len(want) || positions[0] != want[0] || positions[1] != want[1] {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+	if rescored, _ := FuzzyScore("pd", "prod"); rescored != score {
+		t.Errorf("FuzzyMatch score %d disagrees with FuzzyScore %d", score, rescored)
+	}
+}
+
+func TestRankSessions_KeepsNonMatchesWithZeroScore(t *testing.T) {
+	sessions := []*CumulocitySession{
+		{Name: "prod"},
+		{Name: "zzz-unrelated"},
+	}
+
+	ranked := RankSessions(sessions, "prod")
+	if len(ranked) != len(sessions) {
+		t.Fatalf("RankSessions() dropped sessions: got %d, want %d", len(ranked), len(sessions))
+	}
+
+	if ranked[0].Session.Name != "prod" || ranked[0].Score <= 0 {
+		t.Errorf("expected \"prod\" ranked first with a positive score, got %+v", ranked[0])
+	}
+	if ranked[1].Session.Name != "zzz-unrelated" || ranked[1].Score != 0 {
+		t.Errorf("expected the non-matching session last with a zero score, got %+v", ranked[1])
+	}
+}
+
+func TestRankSessions_ReportsMatchedFieldAndPositions(t *testing.T) {
+	sessions := []*CumulocitySession{{Name: "nomatch-here", Host: "https://prod.example.com"}}
+
+	ranked := RankSessions(sessions, "prod")
+	if len(ranked) != 1 {
+		t.Fatalf("expected 1 ranked session, got %d", len(ranked))
+	}
+	if ranked[0].Field != "Host" {
+		t.Errorf("Field = %q, want %q", ranked[0].Field, "Host")
+	}
+	if len(ranked[0].Positions) == 0 {
+		t.Error("expected Positions to be populated for a matching field")
+	}
+}
@@ -64,6 +64,52 @@ func TestCumulocitySession_Description(t *testing.T) {
 	}
 }
 
+func TestMapToSessions_NameAndURITemplates(t *testing.T) {
+	nameTmpl, err := ParseTemplate("name", `{{title (hostname .URL.URL)}}`)
+	if err != nil {
+		t.Fatalf("parsing name template: %v", err)
+	}
+	uriTmpl, err := ParseTemplate("uri", `custom://{{.Item.Vault.Name}}/{{.Item.Title}}`)
+	if err != nil {
+		t.Fatalf("parsing uri template: %v", err)
+	}
+	SetNameTemplate(nameTmpl)
+	SetURITemplate(uriTmpl)
+	t.Cleanup(func() { SetNameTemplate(nil); SetURITemplate(nil) })
+
+	item := Item{ID: "item-1", Title: "My Item", Vault: Vault{ID: "vault-1", Name: "My Vault"}}
+	urls := []URLSource{{URL: "https://prod.example.com", Primary: true}}
+
+	sessions := MapToSessions(item, ItemFields{}, urls, "My Vault", nil)
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions))
+	}
+
+	if got, want := sessions[0].Name, "Prod"; got != want {
+		t.Errorf("Name = %q, want %q", got, want)
+	}
+	if got, want := sessions[0].SessionURI, "custom://My Vault/My Item"; got != want {
+		t.Errorf("SessionURI = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Prod", "c8y", "PROD", "Staging"})
+	want := []string{"c8y", "prod", "staging"}
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeTags() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("NormalizeTags()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := NormalizeTags(nil); got != nil {
+		t.Errorf("NormalizeTags(nil) = %v, want nil", got)
+	}
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
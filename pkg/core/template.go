@@ -0,0 +1,173 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateContext is the data available to a session template: the
+// rendered session, plus the item and URL source it was built from when
+// the caller has them at hand (the picker's Title()/Description() only
+// have the session itself, so those fields are the zero value there).
+type TemplateContext struct {
+	Session *CumulocitySession
+	Item    Item
+	URL     URLSource
+}
+
+// TemplateFuncs are the helpers available to every session template: a
+// small sprig-like subset (trimPrefix, trimSuffix, default, env) plus
+// normalizeURL and join, which cover what the hard-coded formatting they
+// replace already relied on, and hostname/trimProto/title/lower for
+// templates that build a session's Name or SessionURI from its URL.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"default": func(def, val string) string {
+			if val == "" {
+				return def
+			}
+			return val
+		},
+		"env":          os.Getenv,
+		"normalizeURL": NormalizeDisplayURL,
+		"join":         func(sep string, items []string) string { return strings.Join(items, sep) },
+		"hostname":     extractHostname,
+		"trimProto":    trimProto,
+		"title":        titleCase,
+		"lower":        strings.ToLower,
+	}
+}
+
+// trimProto strips a leading "scheme://" from url, if present.
+func trimProto(url string) string {
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		return rest
+	}
+	return url
+}
+
+// titleCase upper-cases the first letter of each whitespace-separated word
+// (strings.Title is deprecated; templates only need this for short labels
+// like hostnames or tags, not full Unicode-aware title casing).
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// DefaultDescriptionTemplate follows CumulocitySession.Description()'s
+// hard-coded "Username=..., Tenant=..., Tags=... | <URI>" formatting as a
+// template, so operators overriding it have a starting point to copy. Its URI
+// part is always .Session.SessionURI, whereas Description() prints an
+// op://<vault>/<item> URI built from the vault and item names when both are
+// known.
+const DefaultDescriptionTemplate = `Username={{.Session.Username}}` +
+	`{{if .Session.Tenant}}, Tenant={{.Session.Tenant}}{{end}}` +
+	`{{if .Session.Tags}}, Tags={{join "," .Session.Tags}}{{end}}` +
+	` | {{.Session.SessionURI}}`
+
+// DefaultTitleTemplate reproduces CumulocitySession.Title().
+const DefaultTitleTemplate = `{{normalizeURL .Session.Host}}`
+
+// ParseTemplate parses text as a session template, naming it name for
+// clearer parse-error messages.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// titleTemplate and descriptionTemplate, when set via SetSessionTemplates,
+// override Title() and Description(); nameTemplate and uriTemplate, set via
+// SetNameTemplate/SetURITemplate, override a session's Name and SessionURI
+// as MapToSessions builds it; documentTemplate, when set via
+// SetDocumentTemplate, is used by RenderDocument to produce the final
+// output document instead of plain JSON/YAML marshaling.
+var (
+	titleTemplate       *template.Template
+	descriptionTemplate *template.Template
+	nameTemplate        *template.Template
+	uriTemplate         *template.Template
+	documentTemplate    *template.Template
+)
+
+// SetSessionTemplates overrides Title() and Description() rendering for
+// every CumulocitySession with user-supplied templates; either may be nil
+// to keep that part's built-in formatting. Call once during startup, after
+// flags and config are parsed -- picker items render on demand from
+// whatever is currently set.
+func SetSessionTemplates(title, description *template.Template) {
+	titleTemplate = title
+	descriptionTemplate = description
+}
+
+// SetNameTemplate overrides the Name MapToSessions assigns to every session
+// built afterwards; nil restores the built-in BuildSessionName formatting.
+func SetNameTemplate(name *template.Template) {
+	nameTemplate = name
+}
+
+// SetURITemplate overrides the SessionURI MapToSessions assigns to every
+// session built afterwards; nil restores the built-in "op://vault/item"
+// formatting.
+func SetURITemplate(uri *template.Template) {
+	uriTemplate = uri
+}
+
+// applyNameAndURITemplates overrides session's Name and SessionURI with the
+// configured nameTemplate/uriTemplate, if any, rendered against session's
+// already-built defaults plus item and urlSource for context; a template
+// that fails to execute leaves that field at its built-in default, the same
+// fallback Title()/Description() use.
+func applyNameAndURITemplates(session *CumulocitySession, item Item, urlSource URLSource) {
+	if nameTemplate != nil {
+		if rendered, err := renderSessionTemplate(nameTemplate, session, item, urlSource); err == nil {
+			session.Name = rendered
+		}
+	}
+	if uriTemplate != nil {
+		if rendered, err := renderSessionTemplate(uriTemplate, session, item, urlSource); err == nil {
+			session.SessionURI = rendered
+		}
+	}
+}
+
+// SetDocumentTemplate overrides the template RenderDocument uses; nil
+// restores the default of "no document template" (callers should fall
+// back to plain JSON/YAML marshaling in that case).
+func SetDocumentTemplate(document *template.Template) {
+	documentTemplate = document
+}
+
+// DocumentTemplate returns the template set by SetDocumentTemplate, or nil
+// if none was set.
+func DocumentTemplate() *template.Template {
+	return documentTemplate
+}
+
+// RenderDocument renders the currently-set document template against
+// session (with a zero Item/URL context), returning an error if none is
+// set -- callers should check DocumentTemplate() != nil first if they want
+// to fall back to another format instead.
+func RenderDocument(session *CumulocitySession) (string, error) {
+	if documentTemplate == nil {
+		return "", fmt.Errorf("no document template configured")
+	}
+	return renderSessionTemplate(documentTemplate, session, Item{}, URLSource{})
+}
+
+func renderSessionTemplate(tmpl *template.Template, session *CumulocitySession, item Item, urlSource URLSource) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, TemplateContext{Session: session, Item: item, URL: urlSource}); err != nil {
+		return "", fmt.Errorf("rendering template %q: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,262 @@
+// Package oidc implements the minimal OAuth2/OIDC client needed to turn a
+// 1Password item's oidc_issuer/oidc_client_id/oidc_client_secret fields into
+// a Cumulocity-compatible bearer token: refreshing a stored refresh token
+// when present, or otherwise running an authorization-code-with-PKCE flow
+// through a loopback redirect on 127.0.0.1.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Config describes the client credentials and (optionally) a stored
+// refresh token needed to obtain a token for one session.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+// TokenResult is the subset of a token endpoint response callers need.
+type TokenResult struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+}
+
+// endpoints is the subset of an OIDC discovery document this package uses.
+type endpoints struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// Obtain returns a bearer token for cfg, refreshing cfg.RefreshToken if
+// present and otherwise running the interactive PKCE flow. A refresh
+// failure (expired or revoked token) falls back to the interactive flow
+// rather than failing outright.
+func Obtain(ctx context.Context, cfg Config) (*TokenResult, error) {
+	ep, err := discover(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.RefreshToken != "" {
+		if result, err := refresh(ctx, ep.TokenEndpoint, cfg); err == nil {
+			return result, nil
+		}
+	}
+
+	return authorizeWithPKCE(ctx, ep, cfg)
+}
+
+// discover fetches the issuer's OpenID Connect discovery document.
+func discover(ctx context.Context, issuer string) (*endpoints, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery document returned status %d", resp.StatusCode)
+	}
+
+	var ep endpoints
+	if err := json.NewDecoder(resp.Body).Decode(&ep); err != nil {
+		return nil, fmt.Errorf("parsing OIDC discovery document: %w", err)
+	}
+	return &ep, nil
+}
+
+// refresh exchanges cfg.RefreshToken for a new token at tokenEndpoint.
+func refresh(ctx context.Context, tokenEndpoint string, cfg Config) (*TokenResult, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {cfg.RefreshToken},
+		"client_id":     {cfg.ClientID},
+	}
+	if cfg.ClientSecret != "" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	result, err := exchangeToken(ctx, tokenEndpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("refreshing OIDC token: %w", err)
+	}
+	if result.RefreshToken == "" {
+		// Issuers are not required to rotate the refresh token on every
+		// use; keep the one we already have if none was returned.
+		result.RefreshToken = cfg.RefreshToken
+	}
+	return result, nil
+}
+
+// authorizeWithPKCE runs an authorization-code-with-PKCE flow against ep,
+// receiving the callback on a loopback HTTP listener bound to 127.0.0.1.
+func authorizeWithPKCE(ctx context.Context, ep *endpoints, cfg Config) (*TokenResult, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("starting loopback listener for OIDC redirect: %w", err)
+	}
+	defer listener.Close()
+
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", listener.Addr().(*net.TCPAddr).Port)
+
+	verifier, challenge, err := generatePKCE()
+	if err != nil {
+		return nil, fmt.Errorf("generating PKCE challenge: %w", err)
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, fmt.Errorf("generating OIDC state: %w", err)
+	}
+
+	authorizeURL, err := url.Parse(ep.AuthorizationEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing authorization endpoint: %w", err)
+	}
+	query := authorizeURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", "openid offline_access")
+	query.Set("state", state)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+	authorizeURL.RawQuery = query.Encode()
+
+	fmt.Printf("Open the following URL to sign in:\n\n  %s\n\n", authorizeURL.String())
+
+	code, err := waitForCallback(ctx, listener, state)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	if cfg.ClientSecret != "" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	result, err := exchangeToken(ctx, ep.TokenEndpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("exchanging authorization code: %w", err)
+	}
+	return result, nil
+}
+
+// waitForCallback serves a single request on listener, expecting the
+// authorization code and matching state on the redirect URI's query string.
+func waitForCallback(ctx context.Context, listener net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if errParam := query.Get("error"); errParam != "" {
+				resultCh <- result{err: fmt.Errorf("authorization failed: %s", errParam)}
+				fmt.Fprintln(w, "Authorization failed, you may close this window.")
+				return
+			}
+			if query.Get("state") != state {
+				resultCh <- result{err: fmt.Errorf("authorization callback had unexpected state")}
+				fmt.Fprintln(w, "Authorization failed, you may close this window.")
+				return
+			}
+			resultCh <- result{code: query.Get("code")}
+			fmt.Fprintln(w, "Signed in, you may close this window.")
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	select {
+	case r := <-resultCh:
+		return r.code, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// exchangeToken POSTs form to tokenEndpoint and decodes the resulting token.
+func exchangeToken(ctx context.Context, tokenEndpoint string, form url.Values) (*TokenResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		Error        string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("parsing token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if body.Error != "" {
+			return nil, fmt.Errorf("token endpoint returned error: %s", body.Error)
+		}
+		return nil, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	return &TokenResult{
+		AccessToken:  body.AccessToken,
+		TokenType:    body.TokenType,
+		RefreshToken: body.RefreshToken,
+	}, nil
+}
+
+// generatePKCE returns a random code verifier and its S256 code challenge.
+func generatePKCE() (verifier, challenge string, err error) {
+	verifier, err = randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	return verifier, challenge, nil
+}
+
+// randomString returns a URL-safe random string derived from n random bytes.
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,127 @@
+package onepassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// Backend adapts Client to core.SecretBackend, so sessions can be listed
+// across 1Password alongside other backends (e.g. pkg/vaultsecrets) and
+// merged with core.ListFromBackends.
+type Backend struct{}
+
+// NewBackend returns a core.SecretBackend backed by the 1Password CLI.
+func NewBackend() *Backend {
+	return &Backend{}
+}
+
+// Scheme implements core.SecretBackend.
+func (b *Backend) Scheme() string { return "op" }
+
+// ListItems implements core.SecretBackend, fanning out across every vault
+// named in vault (comma-separated) the same way Client.List does.
+func (b *Backend) ListItems(vault string, tags []string) ([]core.BackendItem, error) {
+	client := NewClient(vault, tags...)
+	if err := client.ensureChecked(); err != nil {
+		return nil, err
+	}
+
+	vaultNames := client.parseVaultNames()
+	if len(vaultNames) == 0 {
+		return client.itemsFromVault("")
+	}
+
+	var all []core.BackendItem
+	for _, vaultName := range vaultNames {
+		items, err := client.itemsFromVault(vaultName)
+		if err != nil {
+			if len(vaultNames) == 1 {
+				return nil, err
+			}
+			slog.Warn("Failed to search vault", "vault", vaultName, "error", err)
+			continue
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// GetItem implements core.SecretBackend.
+func (b *Backend) GetItem(vault, item string) (core.BackendItem, error) {
+	client := NewClient(vault)
+	if err := client.ensureChecked(); err != nil {
+		return core.BackendItem{}, err
+	}
+
+	vaultNames := parseVaultNamesFromString(vault)
+	if len(vaultNames) == 0 {
+		return client.getBackendItemFromVault("", item)
+	}
+
+	var lastErr error
+	for _, vaultName := range vaultNames {
+		backendItem, err := client.getBackendItemFromVault(vaultName, item)
+		if err == nil {
+			return backendItem, nil
+		}
+		lastErr = err
+		slog.Debug("Item not found in vault", "vault", vaultName, "item", item, "error", err)
+	}
+	return core.BackendItem{}, fmt.Errorf("item '%s' not found in any of the specified vaults [%s]: %w", item, strings.Join(vaultNames, ", "), lastErr)
+}
+
+// getBackendItemFromVault is the core.BackendItem-returning counterpart of
+// getItemFromVault, sharing its "op item get" invocation but stopping short
+// of mapping the item to sessions.
+func (c *Client) getBackendItemFromVault(vaultIdentifier, itemIdentifier string) (core.BackendItem, error) {
+	args := []string{"item", "get", itemIdentifier, "--format", "json"}
+	if vaultIdentifier != "" {
+		args = append(args, "--vault", vaultIdentifier)
+	}
+
+	var output []byte
+	err := withOpRetry(func() error {
+		start := time.Now()
+		slog.Debug("op command", "command", "op "+strings.Join(args, " "))
+
+		cmd := exec.Command("op", args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		slog.Debug("op command completed", "duration_ms", time.Since(start).Milliseconds())
+		if err != nil {
+			return classifyOpError(fmt.Errorf("failed to get item from 1Password: %w", err), stderr.String())
+		}
+		output = out
+		return nil
+	})
+	if err != nil {
+		return core.BackendItem{}, err
+	}
+
+	var item OPItem
+	if err := json.Unmarshal(output, &item); err != nil {
+		return core.BackendItem{}, fmt.Errorf("failed to parse 1Password item: %w", err)
+	}
+
+	vaults, err := c.ListVaults()
+	if err != nil {
+		slog.Warn("Failed to list vaults", "error", err)
+		vaults = make(map[string]string)
+	}
+
+	return toBackendItem(&item, vaults, c.hostResolver()), nil
+}
+
+// ResolveURI implements core.SecretBackend.
+func (b *Backend) ResolveURI(uri string) (vault, item string, err error) {
+	return ParseOPURI(uri)
+}
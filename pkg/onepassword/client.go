@@ -1,6 +1,8 @@
 package onepassword
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,13 +15,55 @@ import (
 
 	"github.com/cli/safeexec"
 	"github.com/pquerna/otp/totp"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
 	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/selector"
 )
 
 type Client struct {
 	Vault   string
 	Tags    []string
 	checked bool // Track if 1Password has been checked for this session
+
+	// HostResolver expands "*"-wildcard and "url-regex"-labeled URLs into
+	// concrete hosts (see hostresolver.go); nil keeps the default
+	// NoopResolver behavior of one literal session per URL.
+	HostResolver HostResolver
+
+	// Cache, when set, is consulted by List/ListWithProgress before
+	// listing from 1Password, and populated with the result afterwards
+	// (see pkg/cache). CacheTTL must also be positive for a cached listing
+	// to be read; with CacheTTL zero every listing comes from 1Password but
+	// is still written to Cache. A nil Cache disables caching entirely.
+	Cache    *cache.Store
+	CacheTTL time.Duration
+
+	// Timeout bounds every "op" invocation the *Context methods make, when
+	// the context passed in doesn't already carry an earlier deadline. Zero
+	// (the default) leaves those calls unbounded, same as before context
+	// support existed.
+	Timeout time.Duration
+}
+
+// withClientTimeout returns ctx as-is if it already has a deadline or
+// c.Timeout isn't positive, otherwise ctx bounded by c.Timeout. The
+// returned cancel must be called once the context is no longer needed (the
+// non-context methods, which pass context.Background(), rely on this to
+// still apply c.Timeout).
+func (c *Client) withClientTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, hasDeadline := ctx.Deadline(); hasDeadline || c.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+// hostResolver returns c.HostResolver, defaulting to NoopResolver so every
+// call site can resolve unconditionally.
+func (c *Client) hostResolver() HostResolver {
+	if c.HostResolver != nil {
+		return c.HostResolver
+	}
+	return NoopResolver{}
 }
 
 type Vault struct {
@@ -45,6 +89,21 @@ func NewClient(vault string, tags ...string) *Client {
 	}
 }
 
+// tagSelector compiles c.Tags into a selector.Selector. The elements of
+// c.Tags are comma-joined before parsing, so "--tags" accepts the full
+// label-selector grammar (see pkg/core/selector) even though the flag is
+// split into pieces upstream; joining with "," round-trips the original
+// expression since that's also the selector's AND separator.
+func (c *Client) tagSelector() (*selector.Selector, error) {
+	return tagSelectorFor(c.Tags)
+}
+
+// tagSelectorFor is the tagSelector logic shared by Client and
+// ConnectClient, since both compile the same --tags value the same way.
+func tagSelectorFor(tags []string) (*selector.Selector, error) {
+	return selector.Parse(strings.Join(tags, ","))
+}
+
 // OPField 1Password custom fields
 type OPField struct {
 	ID          string        `json:"id"`
@@ -86,6 +145,13 @@ type itemFields struct {
 	password   string
 	totpSecret string
 	tenant     string
+
+	// OIDC/OAuth2 fields, read from custom fields labeled oidc_issuer,
+	// oidc_client_id, oidc_client_secret, and refresh_token.
+	oidcIssuer       string
+	oidcClientID     string
+	oidcClientSecret string
+	refreshToken     string
 }
 
 // parseVaultNamesFromString splits a comma-separated vault string and returns a slice of vault names
@@ -170,7 +236,7 @@ func (opi *OPItem) GetTOTPSecret() string {
 	return fields.totpSecret
 }
 
-func check1Password() error {
+func check1Password(ctx context.Context) error {
 	if _, err := safeexec.LookPath("op"); err != nil {
 		return fmt.Errorf("could not find 'op' (1Password CLI). Check if it is installed on your machine")
 	}
@@ -178,21 +244,31 @@ func check1Password() error {
 	// Check if user is signed in
 	start := time.Now()
 	slog.Debug("op command", "command", "op account get")
-	cmd := exec.Command("op", "account", "get")
+	cmd := exec.CommandContext(ctx, "op", "account", "get")
 	err := cmd.Run()
 	duration := time.Since(start)
 	slog.Debug("op command completed", "duration_ms", duration.Milliseconds())
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return fmt.Errorf("not signed in to 1Password. Please run 'op signin' first")
 	}
 
 	return nil
 }
 
-// ensureChecked calls check1Password only once per client session
+// ensureChecked calls check1Password only once per client session.
 func (c *Client) ensureChecked() error {
+	return c.ensureCheckedContext(context.Background())
+}
+
+// ensureCheckedContext is ensureChecked bounded by ctx and c.Timeout.
+func (c *Client) ensureCheckedContext(ctx context.Context) error {
 	if !c.checked {
-		if err := check1Password(); err != nil {
+		ctx, cancel := c.withClientTimeout(ctx)
+		defer cancel()
+		if err := check1Password(ctx); err != nil {
 			return err
 		}
 		c.checked = true
@@ -219,6 +295,18 @@ func (opi *OPItem) extractFields() itemFields {
 		if strings.HasPrefix(strings.ToLower(field.Label), "tenant") && fields.tenant == "" {
 			fields.tenant = field.Value
 		}
+
+		// Extract OIDC/OAuth2 fields, matched by label like tenant above
+		switch strings.ToLower(field.Label) {
+		case "oidc_issuer":
+			fields.oidcIssuer = field.Value
+		case "oidc_client_id":
+			fields.oidcClientID = field.Value
+		case "oidc_client_secret":
+			fields.oidcClientSecret = field.Value
+		case "refresh_token":
+			fields.refreshToken = field.Value
+		}
 	}
 
 	// Handle tenant/username combination (format: tenant/username)
@@ -281,6 +369,23 @@ func (opi *OPItem) collectURLs() []URLSource {
 
 // mapToSessions creates one or more sessions from a 1Password item, handling multiple URLs
 func (c *Client) mapToSessions(item *OPItem, vaults map[string]string) []*core.CumulocitySession {
+	backendItem := toBackendItem(item, vaults, c.hostResolver())
+	return core.MapToSessions(backendItem.Item, backendItem.Fields, backendItem.URLs, backendItem.VaultName, c.Tags)
+}
+
+// mapToSessions is a package-level convenience wrapper for callers (and
+// tests) that don't need per-client tag filtering or host expansion.
+func mapToSessions(item *OPItem, vaults map[string]string) []*core.CumulocitySession {
+	return (&Client{}).mapToSessions(item, vaults)
+}
+
+// toBackendItem converts a 1Password item into the backend-agnostic shape
+// MapToSessions expects, taking its vault's display name from vaults (keyed
+// by vault ID) when listed there and from the item's own Vault.Name
+// otherwise, and
+// expanding any "*"-wildcard or "url-regex"-labeled URL through resolver
+// (see hostresolver.go; NoopResolver{} keeps URLs as literal one-per-URL).
+func toBackendItem(item *OPItem, vaults map[string]string, resolver HostResolver) core.BackendItem {
 	// Determine vault name for URI
 	vaultName := item.Vault.Name
 	if name, found := vaults[item.Vault.ID]; found {
@@ -305,10 +410,17 @@ func (c *Client) mapToSessions(item *OPItem, vaults map[string]string) []*core.C
 		Password:   fields.password,
 		TOTPSecret: fields.totpSecret,
 		Tenant:     fields.tenant,
+
+		OIDCIssuer:       fields.oidcIssuer,
+		OIDCClientID:     fields.oidcClientID,
+		OIDCClientSecret: fields.oidcClientSecret,
+		RefreshToken:     fields.refreshToken,
 	}
 
-	// Collect URLs
-	allURLs := item.collectURLs()
+	// Collect URLs, expanding any wildcard/regex pattern into its concrete
+	// hosts and deduplicating (a resolved host may coincide with one
+	// already listed literally).
+	allURLs := expandURLs(item.collectURLs(), itemHostsField(item), resolver)
 	coreURLs := make([]core.URLSource, len(allURLs))
 	for i, url := range allURLs {
 		coreURLs[i] = core.URLSource{
@@ -319,8 +431,12 @@ func (c *Client) mapToSessions(item *OPItem, vaults map[string]string) []*core.C
 		}
 	}
 
-	// Use unified session mapping with tag filtering
-	return core.MapToSessions(coreItem, coreFields, coreURLs, vaultName, c.Tags)
+	return core.BackendItem{
+		Item:      coreItem,
+		Fields:    coreFields,
+		URLs:      coreURLs,
+		VaultName: vaultName,
+	}
 }
 
 func isUID(v string) bool {
@@ -330,6 +446,15 @@ func isUID(v string) bool {
 }
 
 func (c *Client) ListVaults(name ...string) (map[string]string, error) {
+	return c.ListVaultsContext(context.Background(), name...)
+}
+
+// ListVaultsContext behaves like ListVaults, bounding the "op vault list"
+// invocation by ctx (and c.Timeout, when ctx has no earlier deadline);
+// ctx.Err() is returned as-is when the command fails because ctx was
+// cancelled or timed out, so callers can tell that apart from a real "op"
+// failure.
+func (c *Client) ListVaultsContext(ctx context.Context, name ...string) (map[string]string, error) {
 	vaults := make([]Vault, 0)
 
 	args := []string{
@@ -337,7 +462,7 @@ func (c *Client) ListVaults(name ...string) (map[string]string, error) {
 		"--format", "json",
 	}
 
-	err := c.exec(args, &vaults)
+	err := c.execContext(ctx, args, &vaults)
 
 	vaultMap := make(map[string]string)
 	for _, vault := range vaults {
@@ -349,57 +474,121 @@ func (c *Client) ListVaults(name ...string) (map[string]string, error) {
 	return vaultMap, err
 }
 
+// exec runs `op` with args and decodes its stdout as JSON into data,
+// retrying recoverable failures (see classifyOpError/withOpRetry) -- a
+// transient "couldn't connect to desktop app" or network hiccup no longer
+// fails the whole List.
 func (c *Client) exec(args []string, data any) error {
-	if err := c.ensureChecked(); err != nil {
-		return err
-	}
+	return c.execContext(context.Background(), args, data)
+}
 
-	start := time.Now()
-	slog.Debug("op command", "command", "op "+strings.Join(args, " "))
-	op := exec.Command("op", args...)
-	stdout, err := op.StdoutPipe()
-	if err != nil {
+// execContext is exec bounded by ctx and c.Timeout: the "op" process is
+// started with exec.CommandContext, so a cancelled/expired ctx kills it
+// instead of leaving it to run unsupervised, and the failure it causes is
+// reported back as ctx.Err() rather than an opaque "op" exit error.
+func (c *Client) execContext(ctx context.Context, args []string, data any) error {
+	if err := c.ensureCheckedContext(ctx); err != nil {
 		return err
 	}
 
-	err = op.Start()
-	if err != nil {
-		return err
-	}
+	ctx, cancel := c.withClientTimeout(ctx)
+	defer cancel()
+
+	return withOpRetry(func() error {
+		start := time.Now()
+		slog.Debug("op command", "command", "op "+strings.Join(args, " "))
+
+		op := exec.CommandContext(ctx, "op", args...)
+		var stderr bytes.Buffer
+		op.Stderr = &stderr
 
-	parseErr := json.NewDecoder(stdout).Decode(data)
+		stdout, err := op.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := op.Start(); err != nil {
+			return classifyOpError(err, stderr.String())
+		}
 
-	// wait for command to finish in background
-	go func() {
-		_ = op.Wait() // ignore error as we already have the data
-		duration := time.Since(start)
-		slog.Debug("op command completed", "duration_ms", duration.Milliseconds())
-	}()
+		parseErr := json.NewDecoder(stdout).Decode(data)
+		waitErr := op.Wait()
+		slog.Debug("op command completed", "duration_ms", time.Since(start).Milliseconds())
 
-	return parseErr
+		if waitErr != nil {
+			if ctx.Err() != nil {
+				return NewRecoverableError(ctx.Err(), false)
+			}
+			return classifyOpError(waitErr, stderr.String())
+		}
+		return parseErr
+	})
 }
 
 func (c *Client) List(name ...string) ([]*core.CumulocitySession, error) {
-	if err := c.ensureChecked(); err != nil {
+	return c.ListWithProgress(nil, name...)
+}
+
+// ListWithProgress behaves exactly like List, except that after each vault
+// has been searched it calls progress with the vault name just searched
+// (empty when searching all vaults at once) and the sessions found in it.
+// progress may be nil, in which case this is identical to List; callers that
+// want to stream results to a client as vaults complete (e.g. the native
+// messaging "search" request's streaming mode) pass a non-nil callback.
+func (c *Client) ListWithProgress(progress func(vaultName string, sessions []*core.CumulocitySession), name ...string) ([]*core.CumulocitySession, error) {
+	return c.ListWithProgressContext(context.Background(), progress, name...)
+}
+
+// ListContext behaves like List, bounding every "op" invocation it makes by
+// ctx (and c.Timeout, when ctx has no earlier deadline); a cancelled or
+// timed-out ctx is reported back as the returned error via errors.Is(err,
+// context.Canceled)/context.DeadlineExceeded, so callers can distinguish it
+// from a genuine "op" failure.
+func (c *Client) ListContext(ctx context.Context, name ...string) ([]*core.CumulocitySession, error) {
+	return c.ListWithProgressContext(ctx, nil, name...)
+}
+
+// ListWithProgressContext is ListWithProgress bounded by ctx, same as
+// ListContext.
+func (c *Client) ListWithProgressContext(ctx context.Context, progress func(vaultName string, sessions []*core.CumulocitySession), name ...string) ([]*core.CumulocitySession, error) {
+	if err := c.ensureCheckedContext(ctx); err != nil {
 		return nil, err
 	}
 
 	vaultNames := c.parseVaultNames()
 	slog.Debug("Parsed vault names", "vaultNames", vaultNames, "count", len(vaultNames))
+
+	cacheKey := cache.BuildKey(vaultNames, c.Tags)
+	if c.Cache != nil {
+		if cached, ok := c.Cache.Get(cacheKey, c.CacheTTL); ok {
+			slog.Debug("Serving session list from cache", "vaultNames", vaultNames)
+			if progress != nil {
+				progress("", cached)
+			}
+			return cached, nil
+		}
+	}
+
 	allSessions := make([]*core.CumulocitySession, 0)
 
 	// If no vaults specified, search all vaults
 	if len(vaultNames) == 0 {
-		sessions, err := c.listFromVault("")
+		sessions, err := c.listFromVaultContext(ctx, "")
 		if err != nil {
 			return nil, err
 		}
 		allSessions = append(allSessions, sessions...)
+		if progress != nil {
+			progress("", sessions)
+		}
 	} else {
 		// Search each vault in order
 		for _, vaultName := range vaultNames {
-			sessions, err := c.listFromVault(vaultName)
+			sessions, err := c.listFromVaultContext(ctx, vaultName)
 			if err != nil {
+				// A cancelled or timed-out ctx ends the whole listing
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
 				// For single vault, return error immediately
 				// For multiple vaults, continue with others but log the error
 				if len(vaultNames) == 1 {
@@ -409,6 +598,9 @@ func (c *Client) List(name ...string) ([]*core.CumulocitySession, error) {
 				continue
 			}
 			allSessions = append(allSessions, sessions...)
+			if progress != nil {
+				progress(vaultName, sessions)
+			}
 		}
 	}
 
@@ -421,11 +613,44 @@ func (c *Client) List(name ...string) ([]*core.CumulocitySession, error) {
 	})
 
 	slog.Debug("List method completed", "total_sessions", len(allSessions), "vaults_searched", len(vaultNames))
+
+	if c.Cache != nil {
+		if err := c.Cache.Set(cacheKey, allSessions); err != nil {
+			slog.Warn("Failed to write session cache", "error", err)
+		}
+	}
+
 	return allSessions, nil
 }
 
-// listFromVault searches for sessions in a specific vault (or all vaults if empty)
+// listFromVault searches for sessions in a specific vault (or all vaults if
+// empty), mapping each matching item to its sessions.
 func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, error) {
+	return c.listFromVaultContext(context.Background(), vaultName)
+}
+
+func (c *Client) listFromVaultContext(ctx context.Context, vaultName string) ([]*core.CumulocitySession, error) {
+	items, err := c.itemsFromVaultContext(ctx, vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*core.CumulocitySession, 0, len(items))
+	for _, item := range items {
+		sessions = append(sessions, core.MapToSessions(item.Item, item.Fields, item.URLs, item.VaultName, c.Tags)...)
+	}
+	return sessions, nil
+}
+
+// itemsFromVault is the core.SecretBackend-shaped counterpart of
+// listFromVault: it does the same item-list/fetch/tag-filter work, but
+// returns the backend-agnostic items instead of already-built sessions, so
+// both listFromVault and ListItems can share it.
+func (c *Client) itemsFromVault(vaultName string) ([]core.BackendItem, error) {
+	return c.itemsFromVaultContext(context.Background(), vaultName)
+}
+
+func (c *Client) itemsFromVaultContext(ctx context.Context, vaultName string) ([]core.BackendItem, error) {
 	listArgs := []string{
 		"item", "list",
 		"--format", "json",
@@ -442,7 +667,7 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 			listArgs = append(listArgs, "--vault", vaultName)
 		} else {
 			// Filter by vault name/pattern (additional lookup required)
-			vaults, vaultErr = c.ListVaults(vaultName)
+			vaults, vaultErr = c.ListVaultsContext(ctx, vaultName)
 			if vaultErr != nil {
 				return nil, vaultErr
 			}
@@ -462,8 +687,16 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 		}
 	}
 
-	// Add tags filter if specified
-	if len(c.Tags) > 0 {
+	tagSelector, err := c.tagSelector()
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tags selector: %w", err)
+	}
+
+	// Narrow the op CLI's own listing when every tag is a plain bare
+	// identifier (the legacy comma-separated-OR form); anything using the
+	// richer selector grammar (key=value, negation, in/notin) has no op
+	// CLI equivalent, so it's left to the post-fetch filter below.
+	if len(c.Tags) > 0 && tagSelector.IsLegacyBareList() {
 		for _, tag := range c.Tags {
 			listArgs = append(listArgs, "--tags", tag)
 		}
@@ -473,13 +706,12 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 
 	// First get the list of items
 	items := make([]OPItem, 0)
-	err := c.exec(listArgs, &items)
-	if err != nil {
+	if err := c.execContext(ctx, listArgs, &items); err != nil {
 		return nil, err
 	}
 
 	if len(items) == 0 {
-		return []*core.CumulocitySession{}, nil
+		return []core.BackendItem{}, nil
 	}
 
 	var detailedItems []OPItem
@@ -487,14 +719,14 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 	// Use bulk fetch for multiple items, individual fetch for single item
 	if len(items) > 1 {
 		slog.Debug("Using bulk fetch for multiple items", "count", len(items))
-		detailedItems, err = c.bulkGetItems(listArgs)
+		detailedItems, err = c.bulkGetItemsContext(ctx, listArgs)
 		if err != nil {
 			slog.Warn("Bulk fetch failed, falling back to individual fetches", "error", err)
-			detailedItems, err = c.individualGetItems(items)
+			detailedItems, err = c.individualGetItemsContext(ctx, items)
 		}
 	} else {
 		slog.Debug("Using individual fetch for single item")
-		detailedItems, err = c.individualGetItems(items)
+		detailedItems, err = c.individualGetItemsContext(ctx, items)
 	}
 
 	if err != nil {
@@ -505,14 +737,14 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 
 	// Get vault names for proper display if not already loaded
 	if vaults == nil {
-		vaults, err = c.ListVaults()
+		vaults, err = c.ListVaultsContext(ctx)
 		if err != nil {
 			slog.Warn("Failed to list vaults", "error", err)
 			vaults = make(map[string]string)
 		}
 	}
 
-	sessions := make([]*core.CumulocitySession, 0)
+	backendItems := make([]core.BackendItem, 0)
 	for _, item := range detailedItems {
 		slog.Debug("Processing item", "item_id", item.ID, "item_title", item.Title, "category", item.Category, "urls_count", len(item.URLs), "tags", item.Tags)
 
@@ -520,79 +752,87 @@ func (c *Client) listFromVault(vaultName string) ([]*core.CumulocitySession, err
 			continue
 		}
 
-		// Filter by tags if specified and not already filtered by op command
-		if len(c.Tags) > 0 {
-			hasRequiredTag := false
-			for _, requiredTag := range c.Tags {
-				for _, itemTag := range item.Tags {
-					if strings.EqualFold(itemTag, requiredTag) {
-						hasRequiredTag = true
-						break
-					}
-				}
-				if hasRequiredTag {
-					break
-				}
-			}
-			if !hasRequiredTag {
-				slog.Debug("Skipping item", "item_id", item.ID, "reason", "missing required tags", "required_tags", c.Tags, "item_tags", item.Tags)
-				continue
-			}
+		// Filter by tags if specified. The op command's own "--tags" (above)
+		// is only ever an optimization for the legacy bare-list form, so
+		// the selector is always re-applied here to get the full grammar.
+		if len(c.Tags) > 0 && !tagSelector.Matches(item.Tags) {
+			slog.Debug("Skipping item", "item_id", item.ID, "reason", "tag selector did not match", "tag_selector", tagSelector.String(), "item_tags", item.Tags)
+			continue
 		}
 
-		// Create sessions for this item (may create multiple sessions for multiple URLs)
-		itemSessions := c.mapToSessions(&item, vaults)
-		sessions = append(sessions, itemSessions...)
+		backendItems = append(backendItems, toBackendItem(&item, vaults, c.hostResolver()))
 	}
 
-	slog.Debug("Item filtering completed", "total_items", len(detailedItems), "sessions_created", len(sessions), "vault_name", vaultName)
-	return sessions, nil
+	slog.Debug("Item filtering completed", "total_items", len(detailedItems), "items_mapped", len(backendItems), "vault_name", vaultName)
+	return backendItems, nil
 }
 
-// bulkGetItems efficiently fetches detailed item information using piped commands
-// This eliminates N+1 queries by using: op item list ... | op item get -
+// bulkGetItems efficiently fetches detailed item information using piped
+// commands -- this eliminates N+1 queries by using:
+// op item list ... | op item get - --format json
+//
+// The whole pipeline is retried as a unit on a recoverable failure (see
+// classifyOpError/withOpRetry), classified from whichever of the two
+// commands' stderr is non-empty.
 func (c *Client) bulkGetItems(listArgs []string) ([]OPItem, error) {
-	if err := c.ensureChecked(); err != nil {
+	return c.bulkGetItemsContext(context.Background(), listArgs)
+}
+
+// bulkGetItemsContext is bulkGetItems bounded by ctx and c.Timeout: both
+// halves of the pipeline are started with exec.CommandContext, so a
+// cancelled/expired ctx kills listCmd and getCmd together instead of
+// leaving either to finish writing into a pipe nothing is reading from
+// anymore.
+func (c *Client) bulkGetItemsContext(ctx context.Context, listArgs []string) ([]OPItem, error) {
+	if err := c.ensureCheckedContext(ctx); err != nil {
 		return nil, err
 	}
 
-	start := time.Now()
-	slog.Debug("op command", "command", "op "+strings.Join(listArgs, " ")+" | op item get - --format json")
+	ctx, cancel := c.withClientTimeout(ctx)
+	defer cancel()
 
-	// Create the list command
-	listCmd := exec.Command("op", listArgs...)
+	var output []byte
+	err := withOpRetry(func() error {
+		start := time.Now()
+		slog.Debug("op command", "command", "op "+strings.Join(listArgs, " ")+" | op item get - --format json")
 
-	// Create the get command that reads from list output
-	getCmd := exec.Command("op", "item", "get", "-", "--format", "json")
+		listCmd := exec.CommandContext(ctx, "op", listArgs...)
+		getCmd := exec.CommandContext(ctx, "op", "item", "get", "-", "--format", "json")
 
-	// Connect the commands via pipe
-	pipe, err := listCmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create pipe: %w", err)
-	}
+		var listStderr, getStderr bytes.Buffer
+		listCmd.Stderr = &listStderr
+		getCmd.Stderr = &getStderr
 
-	getCmd.Stdin = pipe
+		pipe, err := listCmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to create pipe: %w", err)
+		}
+		getCmd.Stdin = pipe
 
-	// Start the list command
-	if err := listCmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start list command: %w", err)
-	}
+		if err := listCmd.Start(); err != nil {
+			return classifyOpError(fmt.Errorf("failed to start list command: %w", err), listStderr.String())
+		}
 
-	// Get the output from the get command
-	output, err := getCmd.Output()
-	if err != nil {
-		// Make sure to wait for list command to finish
-		_ = listCmd.Wait()
-		return nil, fmt.Errorf("failed to get detailed items: %w", err)
-	}
+		out, getErr := getCmd.Output()
+		listErr := listCmd.Wait()
+		slog.Debug("op command completed", "duration_ms", time.Since(start).Milliseconds())
 
-	// Wait for list command to finish
-	if err := listCmd.Wait(); err != nil {
-		return nil, fmt.Errorf("list command failed: %w", err)
-	}
+		if ctx.Err() != nil {
+			return NewRecoverableError(ctx.Err(), false)
+		}
+		if getErr != nil {
+			return classifyOpError(fmt.Errorf("failed to get detailed items: %w", getErr), getStderr.String())
+		}
+		if listErr != nil {
+			return classifyOpError(fmt.Errorf("list command failed: %w", listErr), listStderr.String())
+		}
 
-	duration := time.Since(start)
-	slog.Debug("op command completed", "duration_ms", duration.Milliseconds())
+		output = out
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse multiple JSON objects from the output
 	// The output contains multiple pretty-printed JSON objects
@@ -623,14 +863,22 @@ func (c *Client) bulkGetItems(listArgs []string) ([]OPItem, error) {
 
 // individualGetItems fetches detailed information for items one by one (fallback method)
 func (c *Client) individualGetItems(items []OPItem) ([]OPItem, error) {
+	return c.individualGetItemsContext(context.Background(), items)
+}
+
+func (c *Client) individualGetItemsContext(ctx context.Context, items []OPItem) ([]OPItem, error) {
 	detailedItems := make([]OPItem, 0, len(items))
 	for _, item := range items {
+		if ctx.Err() != nil {
+			return detailedItems, ctx.Err()
+		}
+
 		var detailedItem OPItem
 		detailArgs := []string{
 			"item", "get", item.ID,
 			"--format", "json",
 		}
-		if err := c.exec(detailArgs, &detailedItem); err != nil {
+		if err := c.execContext(ctx, detailArgs, &detailedItem); err != nil {
 			slog.Warn("Failed to get item details", "id", item.ID, "error", err)
 			continue
 		}
@@ -657,6 +905,27 @@ func GetTOTPCodeFromSecret(secret string) (string, error) {
 	return GetTOTPCode(secret, totpTime)
 }
 
+// UpdateRefreshToken writes a renewed OIDC refresh token back to the item's
+// refresh_token field via "op item edit", so later invocations can reuse it
+// instead of re-running the interactive authorization flow.
+func (c *Client) UpdateRefreshToken(vaultIdentifier, itemIdentifier, refreshToken string) error {
+	args := []string{"item", "edit", itemIdentifier, "refresh_token=" + refreshToken}
+	if vaultIdentifier != "" {
+		args = append(args, "--vault", vaultIdentifier)
+	}
+
+	start := time.Now()
+	slog.Debug("op command", "command", "op "+strings.Join(args, " "))
+	cmd := exec.Command("op", args...)
+	err := cmd.Run()
+	duration := time.Since(start)
+	slog.Debug("op command completed", "duration_ms", duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to write refresh token back to 1Password item: %w", err)
+	}
+	return nil
+}
+
 // ParseOPURI parses an op://vault/item URI and returns vault and item components
 func ParseOPURI(uri string) (vault, item string, err error) {
 	if !strings.HasPrefix(uri, "op://") {
@@ -685,7 +954,15 @@ func ParseOPURI(uri string) (vault, item string, err error) {
 
 // GetItem retrieves a specific item from 1Password by vault and item identifier
 func (c *Client) GetItem(vaultIdentifier, itemIdentifier string) (*core.CumulocitySession, error) {
-	if err := c.ensureChecked(); err != nil {
+	return c.GetItemContext(context.Background(), vaultIdentifier, itemIdentifier)
+}
+
+// GetItemContext behaves like GetItem, bounding the "op item get"
+// invocation(s) it makes by ctx (and c.Timeout, when ctx has no earlier
+// deadline); a cancelled or timed-out ctx is reported back as the returned
+// error via errors.Is(err, context.Canceled)/context.DeadlineExceeded.
+func (c *Client) GetItemContext(ctx context.Context, vaultIdentifier, itemIdentifier string) (*core.CumulocitySession, error) {
+	if err := c.ensureCheckedContext(ctx); err != nil {
 		return nil, err
 	}
 
@@ -694,17 +971,20 @@ func (c *Client) GetItem(vaultIdentifier, itemIdentifier string) (*core.Cumuloci
 
 	// If no vaults specified, try without vault filter
 	if len(vaultNames) == 0 {
-		return c.getItemFromVault("", itemIdentifier)
+		return c.getItemFromVaultContext(ctx, "", itemIdentifier)
 	}
 
 	// Try each vault in order until we find the item
 	var lastErr error
 	for _, vaultName := range vaultNames {
-		session, err := c.getItemFromVault(vaultName, itemIdentifier)
+		session, err := c.getItemFromVaultContext(ctx, vaultName, itemIdentifier)
 		if err == nil {
 			return session, nil
 		}
 		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		slog.Debug("Item not found in vault", "vault", vaultName, "item", itemIdentifier, "error", err)
 	}
 
@@ -715,20 +995,41 @@ func (c *Client) GetItem(vaultIdentifier, itemIdentifier string) (*core.Cumuloci
 
 // getItemFromVault retrieves an item from a specific vault (or any vault if empty)
 func (c *Client) getItemFromVault(vaultIdentifier, itemIdentifier string) (*core.CumulocitySession, error) {
+	return c.getItemFromVaultContext(context.Background(), vaultIdentifier, itemIdentifier)
+}
+
+func (c *Client) getItemFromVaultContext(ctx context.Context, vaultIdentifier, itemIdentifier string) (*core.CumulocitySession, error) {
 	// Build the op item get command
 	args := []string{"item", "get", itemIdentifier, "--format", "json"}
 	if vaultIdentifier != "" {
 		args = append(args, "--vault", vaultIdentifier)
 	}
 
-	start := time.Now()
-	slog.Debug("op command", "command", "op "+strings.Join(args, " "))
-	cmd := exec.Command("op", args...)
-	output, err := cmd.Output()
-	duration := time.Since(start)
-	slog.Debug("op command completed", "duration_ms", duration.Milliseconds())
+	ctx, cancel := c.withClientTimeout(ctx)
+	defer cancel()
+
+	var output []byte
+	err := withOpRetry(func() error {
+		start := time.Now()
+		slog.Debug("op command", "command", "op "+strings.Join(args, " "))
+
+		cmd := exec.CommandContext(ctx, "op", args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		slog.Debug("op command completed", "duration_ms", time.Since(start).Milliseconds())
+		if err != nil {
+			if ctx.Err() != nil {
+				return NewRecoverableError(ctx.Err(), false)
+			}
+			return classifyOpError(fmt.Errorf("failed to get item from 1Password: %w", err), stderr.String())
+		}
+		output = out
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item from 1Password: %w", err)
+		return nil, err
 	}
 
 	var item OPItem
@@ -737,7 +1038,7 @@ func (c *Client) getItemFromVault(vaultIdentifier, itemIdentifier string) (*core
 	}
 
 	// Get vault information for proper naming
-	vaults, err := c.ListVaults()
+	vaults, err := c.ListVaultsContext(ctx)
 	if err != nil {
 		slog.Warn("Failed to list vaults", "error", err)
 		vaults = make(map[string]string)
@@ -0,0 +1,290 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/selector"
+)
+
+// Environment variables read by NewConnectClientFromEnv, matching the names
+// 1Password's own Connect SDKs use.
+const (
+	envConnectHost  = "OP_CONNECT_HOST"
+	envConnectToken = "OP_CONNECT_TOKEN"
+)
+
+// ConnectClient is a Client-equivalent that lists and fetches items by
+// calling a 1Password Connect Server's REST API directly over HTTP, instead
+// of shelling out to the op CLI. It has no check1Password/safeexec.LookPath
+// dependency, so it works in containers and CI where the interactive CLI
+// can't sign in.
+//
+// ConnectClient exposes the same List/ListVaults/GetItem shape as Client;
+// ConnectBackend adapts it to core.SecretBackend the same way Backend
+// adapts Client, so --backend connect fans out alongside "op" and "vault".
+type ConnectClient struct {
+	Host  string
+	Token string
+	Vault string
+	Tags  []string
+
+	// HostResolver expands "*"-wildcard and "url-regex"-labeled URLs the
+	// same way Client.HostResolver does.
+	HostResolver HostResolver
+
+	httpClient *http.Client
+}
+
+// NewConnectClient returns a ConnectClient for the Connect Server at host,
+// authenticating with token.
+func NewConnectClient(host, token, vault string, tags ...string) *ConnectClient {
+	return &ConnectClient{
+		Host:       strings.TrimRight(host, "/"),
+		Token:      token,
+		Vault:      vault,
+		Tags:       tags,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewConnectClientFromEnv builds a ConnectClient from OP_CONNECT_HOST and
+// OP_CONNECT_TOKEN, erroring if either is unset.
+func NewConnectClientFromEnv(vault string, tags ...string) (*ConnectClient, error) {
+	host := os.Getenv(envConnectHost)
+	token := os.Getenv(envConnectToken)
+	if host == "" || token == "" {
+		return nil, fmt.Errorf("%s and %s must both be set to use the connect backend", envConnectHost, envConnectToken)
+	}
+	return NewConnectClient(host, token, vault, tags...), nil
+}
+
+// parseVaultNames splits c.Vault the same way Client.parseVaultNames does.
+func (c *ConnectClient) parseVaultNames() []string {
+	return parseVaultNamesFromString(c.Vault)
+}
+
+// hostResolver returns c.HostResolver, defaulting to NoopResolver so every
+// call site can resolve unconditionally.
+func (c *ConnectClient) hostResolver() HostResolver {
+	if c.HostResolver == nil {
+		return NoopResolver{}
+	}
+	return c.HostResolver
+}
+
+// tagSelector compiles c.Tags into a selector.Selector.
+func (c *ConnectClient) tagSelector() (*selector.Selector, error) {
+	return tagSelectorFor(c.Tags)
+}
+
+// get performs an authenticated GET against the Connect Server and decodes
+// the JSON response body into out.
+func (c *ConnectClient) get(path string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, c.Host+path, nil)
+	if err != nil {
+		return fmt.Errorf("building connect request for %s: %w", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("connect request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+	slog.Debug("connect request completed", "path", path, "status", resp.StatusCode, "duration_ms", time.Since(start).Milliseconds())
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("connect server returned %s for %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// connectVault is the subset of the Connect Server's vault representation
+// ListVaults needs.
+type connectVault struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListVaults returns every vault the Connect token can access, optionally
+// narrowed by a case-insensitive substring of name[0] -- the same contract
+// as Client.ListVaults.
+func (c *ConnectClient) ListVaults(name ...string) (map[string]string, error) {
+	var vaults []connectVault
+	if err := c.get("/v1/vaults", &vaults); err != nil {
+		return nil, err
+	}
+
+	vaultMap := make(map[string]string)
+	for _, vault := range vaults {
+		if len(name) == 0 || strings.Contains(strings.ToLower(vault.Name), strings.ToLower(name[0])) {
+			vaultMap[vault.ID] = vault.Name
+		}
+	}
+	return vaultMap, nil
+}
+
+// List returns every Cumulocity session reachable across c.Vault's vaults.
+func (c *ConnectClient) List() ([]*core.CumulocitySession, error) {
+	vaultNames := c.parseVaultNames()
+	if len(vaultNames) == 0 {
+		return c.listFromVault("")
+	}
+
+	var allSessions []*core.CumulocitySession
+	for _, vaultName := range vaultNames {
+		sessions, err := c.listFromVault(vaultName)
+		if err != nil {
+			if len(vaultNames) == 1 {
+				return nil, err
+			}
+			slog.Warn("Failed to search vault", "vault", vaultName, "error", err)
+			continue
+		}
+		allSessions = append(allSessions, sessions...)
+	}
+
+	sort.Slice(allSessions, func(i, j int) bool {
+		return core.NormalizeDisplayURL(allSessions[i].Host) < core.NormalizeDisplayURL(allSessions[j].Host)
+	})
+	return allSessions, nil
+}
+
+func (c *ConnectClient) listFromVault(vaultName string) ([]*core.CumulocitySession, error) {
+	items, err := c.itemsFromVault(vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*core.CumulocitySession, 0, len(items))
+	for _, item := range items {
+		sessions = append(sessions, core.MapToSessions(item.Item, item.Fields, item.URLs, item.VaultName, c.Tags)...)
+	}
+	return sessions, nil
+}
+
+// itemsFromVault is the core.SecretBackend-shaped counterpart of
+// listFromVault: it lists every Login item in vaultName (or every vault the
+// token can see, if empty), fetches each one's full fields, and converts it
+// to a core.BackendItem the same way Client.itemsFromVault does.
+func (c *ConnectClient) itemsFromVault(vaultName string) ([]core.BackendItem, error) {
+	vaults, err := c.vaultIDsFor(vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	tagSelector, err := c.tagSelector()
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tags selector: %w", err)
+	}
+
+	backendItems := make([]core.BackendItem, 0)
+	for vaultID := range vaults {
+		var summaries []OPItem
+		if err := c.get(fmt.Sprintf("/v1/vaults/%s/items", url.PathEscape(vaultID)), &summaries); err != nil {
+			return nil, err
+		}
+
+		for _, summary := range summaries {
+			var item OPItem
+			if err := c.get(fmt.Sprintf("/v1/vaults/%s/items/%s", url.PathEscape(vaultID), url.PathEscape(summary.ID)), &item); err != nil {
+				return nil, err
+			}
+			if item.Skip() {
+				continue
+			}
+			if len(c.Tags) > 0 && !tagSelector.Matches(item.Tags) {
+				continue
+			}
+			backendItems = append(backendItems, toBackendItem(&item, vaults, c.hostResolver()))
+		}
+	}
+	return backendItems, nil
+}
+
+// vaultIDsFor resolves vaultName (a name, ID, or "" for every vault the
+// token can see) to the set of vault IDs/names itemsFromVault should list.
+func (c *ConnectClient) vaultIDsFor(vaultName string) (map[string]string, error) {
+	if vaultName == "" {
+		return c.ListVaults()
+	}
+	if isUID(vaultName) {
+		vaults, err := c.ListVaults()
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := vaults[vaultName]; ok {
+			return map[string]string{vaultName: name}, nil
+		}
+		return map[string]string{vaultName: vaultName}, nil
+	}
+
+	vaults, err := c.ListVaults(vaultName)
+	if err != nil {
+		return nil, err
+	}
+	if len(vaults) == 0 {
+		return nil, fmt.Errorf("Vault '%s' not found", vaultName)
+	}
+	return vaults, nil
+}
+
+// GetItem retrieves a specific item from 1Password by vault and item
+// identifier, trying every vault named in vaultIdentifier in turn.
+func (c *ConnectClient) GetItem(vaultIdentifier, itemIdentifier string) (*core.CumulocitySession, error) {
+	backendItem, err := c.getBackendItem(vaultIdentifier, itemIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := core.MapToSessions(backendItem.Item, backendItem.Fields, backendItem.URLs, backendItem.VaultName, c.Tags)
+	if len(sessions) > 0 {
+		return sessions[0], nil
+	}
+	return nil, fmt.Errorf("no valid session found for item")
+}
+
+// getBackendItem is the core.BackendItem-returning counterpart of GetItem,
+// shared with ConnectBackend so it doesn't have to map to sessions twice.
+func (c *ConnectClient) getBackendItem(vaultIdentifier, itemIdentifier string) (core.BackendItem, error) {
+	vaultNames := parseVaultNamesFromString(vaultIdentifier)
+	if len(vaultNames) == 0 {
+		vaultNames = []string{""}
+	}
+
+	var lastErr error
+	for _, vaultName := range vaultNames {
+		vaults, err := c.vaultIDsFor(vaultName)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for vaultID := range vaults {
+			var item OPItem
+			if err := c.get(fmt.Sprintf("/v1/vaults/%s/items/%s", url.PathEscape(vaultID), url.PathEscape(itemIdentifier)), &item); err != nil {
+				lastErr = err
+				continue
+			}
+			allVaults, err := c.ListVaults()
+			if err != nil {
+				allVaults = vaults
+			}
+			return toBackendItem(&item, allVaults, c.hostResolver()), nil
+		}
+	}
+	return core.BackendItem{}, fmt.Errorf("item '%s' not found in any of the specified vaults [%s]: %w", itemIdentifier, strings.Join(vaultNames, ", "), lastErr)
+}
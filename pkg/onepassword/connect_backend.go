@@ -0,0 +1,62 @@
+package onepassword
+
+import "github.com/thomaswinkler/c8y-session-1password/pkg/core"
+
+// ConnectBackend adapts ConnectClient to core.SecretBackend, the same way
+// Backend adapts the op CLI-backed Client, so --backend connect fans out
+// alongside "op" and "vault" (see ListFromBackends).
+type ConnectBackend struct {
+	client *ConnectClient
+}
+
+// NewConnectBackend returns a core.SecretBackend backed by the Connect
+// Server at host, authenticating with token.
+func NewConnectBackend(host, token string) *ConnectBackend {
+	return &ConnectBackend{client: NewConnectClient(host, token, "")}
+}
+
+// NewConnectBackendFromEnv returns a core.SecretBackend configured from
+// OP_CONNECT_HOST and OP_CONNECT_TOKEN, erroring if either is unset.
+func NewConnectBackendFromEnv() (*ConnectBackend, error) {
+	client, err := NewConnectClientFromEnv("")
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectBackend{client: client}, nil
+}
+
+// Scheme implements core.SecretBackend. Connect items are the same
+// 1Password items the "op" backend reads, so they keep the "op" URI scheme.
+func (b *ConnectBackend) Scheme() string { return "op" }
+
+// ListItems implements core.SecretBackend, fanning out across every vault
+// named in vault (comma-separated). Unlike Backend.ListItems, which skips a
+// failing vault when several are named, the first vault that fails ends the
+// listing with its error.
+func (b *ConnectBackend) ListItems(vault string, tags []string) ([]core.BackendItem, error) {
+	b.client.Tags = tags
+	vaultNames := parseVaultNamesFromString(vault)
+	if len(vaultNames) == 0 {
+		return b.client.itemsFromVault("")
+	}
+
+	var all []core.BackendItem
+	for _, vaultName := range vaultNames {
+		items, err := b.client.itemsFromVault(vaultName)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// GetItem implements core.SecretBackend.
+func (b *ConnectBackend) GetItem(vault, item string) (core.BackendItem, error) {
+	return b.client.getBackendItem(vault, item)
+}
+
+// ResolveURI implements core.SecretBackend.
+func (b *ConnectBackend) ResolveURI(uri string) (vault, item string, err error) {
+	return ParseOPURI(uri)
+}
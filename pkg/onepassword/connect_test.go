@@ -0,0 +1,164 @@
+package onepassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestConnectServer serves a single vault "vault1" ("Employee") with one
+// Login item, the way a real 1Password Connect Server would respond to
+// GET /v1/vaults, GET /v1/vaults/{id}/items, and GET /v1/vaults/{id}/items/{id}.
+func newTestConnectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	item := OPItem{
+		ID:       "item1",
+		Title:    "Prod Service",
+		Category: "LOGIN",
+		Vault:    OPVault{ID: "vault1", Name: "Employee"},
+		Tags:     []string{"c8y", "prod"},
+		URLs:     []OPURL{{Href: "https://prod.example.com", Primary: true}},
+		Fields: []OPField{
+			{ID: "username", Purpose: "USERNAME", Value: "alice"},
+			{ID: "password", Purpose: "PASSWORD", Value: "s3cret"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/vaults", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]connectVault{{ID: "vault1", Name: "Employee"}})
+	})
+	mux.HandleFunc("/v1/vaults/vault1/items", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]OPItem{item})
+	})
+	mux.HandleFunc("/v1/vaults/vault1/items/item1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(item)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConnectClient_ListVaults(t *testing.T) {
+	server := newTestConnectServer(t)
+	client := NewConnectClient(server.URL, "test-token", "")
+
+	vaults, err := client.ListVaults()
+	if err != nil {
+		t.Fatalf("ListVaults() error: %v", err)
+	}
+	if got, want := vaults["vault1"], "Employee"; got != want {
+		t.Errorf("ListVaults()[%q] = %q, want %q", "vault1", got, want)
+	}
+}
+
+func TestConnectClient_List(t *testing.T) {
+	server := newTestConnectServer(t)
+	client := NewConnectClient(server.URL, "test-token", "Employee")
+
+	sessions, err := client.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("List() returned %d sessions, want 1", len(sessions))
+	}
+	if got, want := sessions[0].Username, "alice"; got != want {
+		t.Errorf("Username = %q, want %q", got, want)
+	}
+	if got, want := sessions[0].Host, "https://prod.example.com"; got != want {
+		t.Errorf("Host = %q, want %q", got, want)
+	}
+}
+
+func TestConnectClient_List_FiltersByTag(t *testing.T) {
+	server := newTestConnectServer(t)
+	client := NewConnectClient(server.URL, "test-token", "Employee", "staging")
+
+	sessions, err := client.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("List() returned %d sessions for a non-matching tag, want 0", len(sessions))
+	}
+}
+
+func TestConnectClient_GetItem(t *testing.T) {
+	server := newTestConnectServer(t)
+	client := NewConnectClient(server.URL, "test-token", "")
+
+	session, err := client.GetItem("Employee", "item1")
+	if err != nil {
+		t.Fatalf("GetItem() error: %v", err)
+	}
+	if got, want := session.Username, "alice"; got != want {
+		t.Errorf("Username = %q, want %q", got, want)
+	}
+}
+
+func TestConnectClient_GetItem_NotFound(t *testing.T) {
+	server := newTestConnectServer(t)
+	client := NewConnectClient(server.URL, "test-token", "")
+
+	if _, err := client.GetItem("Employee", "missing-item"); err == nil {
+		t.Error("expected an error for a missing item")
+	}
+}
+
+func TestNewConnectClientFromEnv_RequiresBothVars(t *testing.T) {
+	t.Setenv(envConnectHost, "")
+	t.Setenv(envConnectToken, "")
+	if _, err := NewConnectClientFromEnv(""); err == nil {
+		t.Error("expected an error when OP_CONNECT_HOST/OP_CONNECT_TOKEN are unset")
+	}
+
+	t.Setenv(envConnectHost, "https://connect.example.com")
+	t.Setenv(envConnectToken, "test-token")
+	client, err := NewConnectClientFromEnv("")
+	if err != nil {
+		t.Fatalf("NewConnectClientFromEnv() error: %v", err)
+	}
+	if got, want := client.Host, "https://connect.example.com"; got != want {
+		t.Errorf("Host = %q, want %q", got, want)
+	}
+}
+
+func TestConnectBackend_ListItems(t *testing.T) {
+	server := newTestConnectServer(t)
+	backend := NewConnectBackend(server.URL, "test-token")
+
+	items, err := backend.ListItems("Employee", nil)
+	if err != nil {
+		t.Fatalf("ListItems() error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("ListItems() returned %d items, want 1", len(items))
+	}
+	if got, want := backend.Scheme(), "op"; got != want {
+		t.Errorf("Scheme() = %q, want %q", got, want)
+	}
+}
+
+func TestConnectClient_get_ErrorsOnNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewConnectClient(server.URL, "test-token", "")
+	var out []connectVault
+	err := client.get("/v1/vaults", &out)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("get() error = %v, want it to mention the response body", err)
+	}
+}
@@ -0,0 +1,59 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClient_withClientTimeout_AppliesTimeoutWhenNoDeadline(t *testing.T) {
+	client := &Client{Timeout: 10 * time.Millisecond}
+
+	ctx, cancel := client.withClientTimeout(context.Background())
+	defer cancel()
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		t.Fatal("expected withClientTimeout to apply a deadline from Client.Timeout")
+	}
+
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("ctx.Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestClient_withClientTimeout_LeavesExistingDeadlineAlone(t *testing.T) {
+	client := &Client{Timeout: time.Hour}
+
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	ctx, innerCancel := client.withClientTimeout(parent)
+	defer innerCancel()
+
+	if ctx != parent {
+		t.Error("expected withClientTimeout to leave a context with an existing deadline unchanged")
+	}
+}
+
+func TestClient_withClientTimeout_NoopWhenTimeoutUnset(t *testing.T) {
+	client := &Client{}
+
+	ctx, cancel := client.withClientTimeout(context.Background())
+	defer cancel()
+
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		t.Error("expected no deadline when Client.Timeout is zero")
+	}
+}
+
+func TestClient_GetItemContext_CancelledContext(t *testing.T) {
+	client := NewClient("")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetItemContext(ctx, "", "item1")
+	if err == nil {
+		t.Fatal("expected an error for an already-cancelled context")
+	}
+}
@@ -0,0 +1,82 @@
+package onepassword
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// cacheAnyAge is the ttl Diff passes to Store.Get in --cache mode, so a
+// cached listing of any age is read rather than none (Store.Get treats a
+// non-positive ttl as "never use the cache").
+const cacheAnyAge = 100 * 365 * 24 * time.Hour
+
+// Diff compares localSession to the current state of its matching
+// 1Password item, found via localSession.SessionURI ("op://vault/item").
+// With useCache, the comparison is made against a locally cached vault
+// listing (see pkg/cache) instead of fetching the item from op, so drift
+// can be checked offline; useCache requires c.Cache to already be set and
+// populated (e.g. by "cache refresh").
+func (c *Client) Diff(localSession *core.CumulocitySession, useCache bool) (*core.SessionDiff, error) {
+	if localSession == nil {
+		return nil, fmt.Errorf("cannot diff a nil session")
+	}
+
+	vault, item, err := ParseOPURI(localSession.SessionURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session URI %q: %w", localSession.SessionURI, err)
+	}
+
+	var remoteSessions []*core.CumulocitySession
+	if useCache {
+		remoteSessions, err = c.cachedSessionsForItem(vault, item)
+	} else {
+		remoteSessions, err = c.remoteSessionsForItem(vault, item)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return core.NewSessionDiff(localSession, remoteSessions), nil
+}
+
+// remoteSessionsForItem fetches the item behind vault/item (the same "op
+// item get" invocation as getItemFromVault/getBackendItemFromVault) and
+// runs it back through core.MapToSessions itself, so Diff sees every
+// URL-derived session for the item rather than just the first.
+func (c *Client) remoteSessionsForItem(vault, item string) ([]*core.CumulocitySession, error) {
+	backendItem, err := c.getBackendItemFromVault(vault, item)
+	if err != nil {
+		return nil, err
+	}
+	return core.MapToSessions(backendItem.Item, backendItem.Fields, backendItem.URLs, backendItem.VaultName, c.Tags), nil
+}
+
+// cachedSessionsForItem reads c.Cache's listing for vault and returns the
+// sessions matching item (by ItemID or ItemName), so Diff can compare
+// against every URL-derived session the cache has for it without hitting
+// op.
+func (c *Client) cachedSessionsForItem(vault, item string) ([]*core.CumulocitySession, error) {
+	if c.Cache == nil {
+		return nil, fmt.Errorf("--cache requires a populated session cache; run \"cache refresh\" first")
+	}
+
+	key := cache.BuildKey([]string{vault}, c.Tags)
+	sessions, ok := c.Cache.Get(key, cacheAnyAge)
+	if !ok {
+		return nil, fmt.Errorf("no cached listing found for vault %q; run \"cache refresh\" first", vault)
+	}
+
+	var matching []*core.CumulocitySession
+	for _, session := range sessions {
+		if session.ItemID == item || session.ItemName == item {
+			matching = append(matching, session)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("item %q not found in cached listing for vault %q; run \"cache refresh\" first", item, vault)
+	}
+	return matching, nil
+}
@@ -0,0 +1,110 @@
+package onepassword
+
+import (
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/cache"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func openTestCacheStore(t *testing.T) *cache.Store {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	store, err := cache.Open()
+	if err != nil {
+		t.Fatalf("cache.Open() error: %v", err)
+	}
+	return store
+}
+
+func TestClient_Diff_CacheMode(t *testing.T) {
+	store := openTestCacheStore(t)
+	key := cache.BuildKey([]string{"Employee"}, nil)
+	remote := &core.CumulocitySession{
+		ItemID:   "item1",
+		Host:     "https://tenant.eu-latest.cumulocity.com",
+		Tenant:   "t12345",
+		Username: "admin",
+	}
+	if err := store.Set(key, []*core.CumulocitySession{remote}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	client := NewClient("")
+	client.Cache = store
+
+	local := &core.CumulocitySession{
+		SessionURI: "op://Employee/item1",
+		Host:       "https://tenant.eu-latest.cumulocity.com",
+		Tenant:     "t99999",
+		Username:   "admin",
+	}
+
+	diff, err := client.Diff(local, true)
+	if err != nil {
+		t.Fatalf("Diff() error: %v", err)
+	}
+	if !diff.Drifted() {
+		t.Fatal("expected drift between local and cached tenant")
+	}
+}
+
+// TestClient_Diff_CacheMode_DefaultTags exercises the same cache key a real
+// "cache refresh" (defaulting to the "c8y" tag) followed by "diff --cache"
+// would use, rather than hand-building a key that happens to match.
+func TestClient_Diff_CacheMode_DefaultTags(t *testing.T) {
+	store := openTestCacheStore(t)
+	key := cache.BuildKey([]string{"Employee"}, []string{"c8y"})
+	remote := &core.CumulocitySession{
+		ItemID:   "item1",
+		Host:     "https://tenant.eu-latest.cumulocity.com",
+		Tenant:   "t12345",
+		Username: "admin",
+	}
+	if err := store.Set(key, []*core.CumulocitySession{remote}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	client := NewClient("", "c8y")
+	client.Cache = store
+
+	local := &core.CumulocitySession{
+		SessionURI: "op://Employee/item1",
+		Host:       "https://tenant.eu-latest.cumulocity.com",
+		Tenant:     "t99999",
+		Username:   "admin",
+	}
+
+	diff, err := client.Diff(local, true)
+	if err != nil {
+		t.Fatalf("Diff() error: %v", err)
+	}
+	if !diff.Drifted() {
+		t.Fatal("expected drift between local and cached tenant")
+	}
+}
+
+func TestClient_Diff_CacheMode_RequiresCache(t *testing.T) {
+	client := NewClient("")
+	local := &core.CumulocitySession{SessionURI: "op://Employee/item1"}
+
+	if _, err := client.Diff(local, true); err == nil {
+		t.Error("expected an error when --cache is used without a configured Cache")
+	}
+}
+
+func TestClient_Diff_InvalidURI(t *testing.T) {
+	client := NewClient("")
+	local := &core.CumulocitySession{SessionURI: "not-a-uri"}
+
+	if _, err := client.Diff(local, true); err == nil {
+		t.Error("expected an error for an invalid session URI")
+	}
+}
+
+func TestClient_Diff_NilSession(t *testing.T) {
+	client := NewClient("")
+	if _, err := client.Diff(nil, false); err == nil {
+		t.Error("expected an error for a nil session")
+	}
+}
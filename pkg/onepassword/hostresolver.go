@@ -0,0 +1,197 @@
+package onepassword
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// hostsFieldLabel is the sibling custom field a wildcard or url-regex item
+// uses to enumerate the concrete hosts a pattern should expand to.
+const hostsFieldLabel = "hosts"
+
+// HostResolver expands a single URL pattern (a "*" wildcard host, or a
+// regular expression when paired with a "url-regex"-labeled field) into the
+// concrete hosts it should become one session each for. hostsField is the
+// item's "hosts" field value, if any (one host per line); resolvers that
+// don't need it ignore it.
+type HostResolver interface {
+	Resolve(pattern string, hostsField string) ([]string, error)
+}
+
+// NoopResolver returns pattern unexpanded -- the default, and the behavior
+// this tool has always had: one session per literal URL, "*" and all.
+type NoopResolver struct{}
+
+// Resolve implements HostResolver.
+func (NoopResolver) Resolve(pattern string, hostsField string) ([]string, error) {
+	return []string{pattern}, nil
+}
+
+// DNSResolver expands a "*" wildcard in pattern via an "_c8y._tcp" SRV
+// lookup on the domain following the wildcard, substituting each SRV
+// target's first label for "*". When the lookup errors or returns nothing,
+// it falls back to substituting "*" with each line of hostsField instead.
+type DNSResolver struct {
+	// LookupSRV overrides net.LookupSRV for tests; nil uses the real
+	// resolver.
+	LookupSRV func(service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
+// Resolve implements HostResolver.
+func (r DNSResolver) Resolve(pattern string, hostsField string) ([]string, error) {
+	if !strings.Contains(pattern, "*") {
+		return []string{pattern}, nil
+	}
+
+	if labels := r.lookupLabels(pattern); len(labels) > 0 {
+		return substituteWildcard(pattern, labels), nil
+	}
+
+	hosts := splitHostsField(hostsField)
+	if len(hosts) == 0 {
+		return []string{pattern}, nil
+	}
+	return substituteWildcard(pattern, hosts), nil
+}
+
+// lookupLabels attempts the "_c8y._tcp" SRV lookup on pattern's domain,
+// returning the first label of every target found (or nil on any error).
+func (r DNSResolver) lookupLabels(pattern string) []string {
+	domain := wildcardDomain(pattern)
+	if domain == "" {
+		return nil
+	}
+
+	lookup := r.LookupSRV
+	if lookup == nil {
+		lookup = net.LookupSRV
+	}
+
+	_, srvs, err := lookup("c8y", "tcp", domain)
+	if err != nil || len(srvs) == 0 {
+		return nil
+	}
+
+	labels := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		target := strings.TrimSuffix(srv.Target, ".")
+		if label, _, ok := strings.Cut(target, "."); ok {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// wildcardDomain extracts the domain suffix following the wildcard's
+// hostname label, e.g. "https://xyz-*.example.com/" -> "example.com".
+func wildcardDomain(pattern string) string {
+	host := pattern
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+
+	_, suffix, ok := strings.Cut(host, "*")
+	if !ok {
+		return ""
+	}
+	return strings.TrimPrefix(suffix, ".")
+}
+
+// substituteWildcard replaces the single "*" in pattern with each of
+// labels in turn, returning one URL per label.
+func substituteWildcard(pattern string, labels []string) []string {
+	hosts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		hosts = append(hosts, strings.Replace(pattern, "*", label, 1))
+	}
+	return hosts
+}
+
+// RegexResolver treats pattern as a regular expression and returns every
+// line of hostsField it matches, for items whose URL field is labeled
+// "url-regex" instead of holding a literal or wildcard URL.
+type RegexResolver struct{}
+
+// Resolve implements HostResolver.
+func (RegexResolver) Resolve(pattern string, hostsField string) ([]string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, host := range splitHostsField(hostsField) {
+		if re.MatchString(host) {
+			matched = append(matched, host)
+		}
+	}
+	return matched, nil
+}
+
+// splitHostsField parses a "hosts" field's newline-separated value into
+// its non-empty, trimmed entries.
+func splitHostsField(hostsField string) []string {
+	var hosts []string
+	for _, line := range strings.Split(hostsField, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			hosts = append(hosts, line)
+		}
+	}
+	return hosts
+}
+
+// expandURLs runs each of urls through resolver (RegexResolver for a
+// "url-regex"-labeled entry, resolver itself for anything containing "*",
+// otherwise left as-is), flattening the results into one URLSource per
+// resolved host and dropping duplicates while preserving first-seen (and
+// so primary-first) order.
+func expandURLs(urls []URLSource, hostsField string, resolver HostResolver) []URLSource {
+	expanded := make([]URLSource, 0, len(urls))
+	seen := make(map[string]bool, len(urls))
+
+	for _, u := range urls {
+		hosts, err := resolveURLSource(u, hostsField, resolver)
+		if err != nil || len(hosts) == 0 {
+			hosts = []string{u.URL}
+		}
+
+		for _, host := range hosts {
+			if seen[host] {
+				continue
+			}
+			seen[host] = true
+			expanded = append(expanded, URLSource{URL: host, Label: u.Label, Primary: u.Primary, Source: u.Source})
+		}
+	}
+
+	return expanded
+}
+
+// resolveURLSource picks the resolver a URLSource needs: RegexResolver for
+// a "url-regex" label, the configured resolver for a "*" wildcard, or a
+// pass-through for a plain literal URL.
+func resolveURLSource(u URLSource, hostsField string, resolver HostResolver) ([]string, error) {
+	switch {
+	case u.Label == "url-regex":
+		return RegexResolver{}.Resolve(u.URL, hostsField)
+	case strings.Contains(u.URL, "*"):
+		return resolver.Resolve(u.URL, hostsField)
+	default:
+		return []string{u.URL}, nil
+	}
+}
+
+// itemHostsField returns the value of item's "hosts" custom field, the
+// sibling field DNSResolver and RegexResolver enumerate against, or "" if
+// the item doesn't have one.
+func itemHostsField(item *OPItem) string {
+	for _, field := range item.Fields {
+		if strings.EqualFold(field.Label, hostsFieldLabel) {
+			return field.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,232 @@
+package onepassword
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestNoopResolver_PassesThroughUnchanged(t *testing.T) {
+	hosts, err := NoopResolver{}.Resolve("https://*.example.com/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"https://*.example.com/"}) {
+		t.Errorf("expected the pattern back unchanged, got %v", hosts)
+	}
+}
+
+func TestDNSResolver_SubstitutesSRVTargetLabels(t *testing.T) {
+	r := DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			if service != "c8y" || proto != "tcp" || name != "example.com" {
+				t.Fatalf("unexpected lookup args: %s %s %s", service, proto, name)
+			}
+			return "", []*net.SRV{
+				{Target: "xyz-01.example.com."},
+				{Target: "xyz-02.example.com."},
+			}, nil
+		},
+	}
+
+	hosts, err := r.Resolve("https://*.example.com/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"https://xyz-01.example.com/", "https://xyz-02.example.com/"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("expected %v, got %v", want, hosts)
+	}
+}
+
+func TestDNSResolver_FallsBackToHostsFieldOnLookupFailure(t *testing.T) {
+	r := DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+	}
+
+	hosts, err := r.Resolve("https://*.example.com/", "xyz-01\nxyz-02\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"https://xyz-01.example.com/", "https://xyz-02.example.com/"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("expected %v, got %v", want, hosts)
+	}
+}
+
+func TestDNSResolver_ReturnsPatternWhenNothingResolves(t *testing.T) {
+	r := DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+	}
+
+	hosts, err := r.Resolve("https://*.example.com/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"https://*.example.com/"}) {
+		t.Errorf("expected the pattern unchanged, got %v", hosts)
+	}
+}
+
+func TestDNSResolver_PassesThroughNonWildcardPattern(t *testing.T) {
+	r := DNSResolver{}
+	hosts, err := r.Resolve("https://literal.example.com/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"https://literal.example.com/"}) {
+		t.Errorf("expected the literal URL unchanged, got %v", hosts)
+	}
+}
+
+func TestRegexResolver_FiltersHostsFieldByPattern(t *testing.T) {
+	hosts, err := RegexResolver{}.Resolve(`^tenant\d+\.example\.com$`, "tenant1.example.com\nother.example.com\ntenant2.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"tenant1.example.com", "tenant2.example.com"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("expected %v, got %v", want, hosts)
+	}
+}
+
+func TestRegexResolver_InvalidPatternErrors(t *testing.T) {
+	if _, err := (RegexResolver{}).Resolve("(", "anything"); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func TestWildcardDomain(t *testing.T) {
+	tests := map[string]string{
+		"https://*.example.com/":       "example.com",
+		"https://xyz-*.example.com":    "example.com",
+		"xyz-*.example.com":            "example.com",
+		"https://literal.example.com/": "",
+	}
+	for pattern, want := range tests {
+		if got := wildcardDomain(pattern); got != want {
+			t.Errorf("wildcardDomain(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestSplitHostsField(t *testing.T) {
+	got := splitHostsField("\n  tenant1.example.com \n\ntenant2.example.com\n  \n")
+	want := []string{"tenant1.example.com", "tenant2.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestToBackendItem_ExpandsWildcardPreservingPrimaryOrder covers the
+// request's required scenario: mixed literal + wildcard URLs, with the
+// primary URL (literal here) still sorted first after expansion.
+func TestToBackendItem_ExpandsWildcardPreservingPrimaryOrder(t *testing.T) {
+	item := &OPItem{
+		ID:       "item-1",
+		Title:    "Wildcard Item",
+		Category: "LOGIN",
+		Vault:    OPVault{ID: "vault-1", Name: "Shared Vault"},
+		URLs: []OPURL{
+			{Label: "website", Primary: true, Href: "https://primary.example.com/"},
+			{Label: "website", Href: "https://*.example.com/"},
+		},
+		Fields: []OPField{
+			{Label: "hosts", Value: "xyz-01\nxyz-02"},
+		},
+		Tags: []string{"c8y"},
+	}
+
+	backendItem := toBackendItem(item, nil, DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+	})
+
+	var hosts []string
+	for _, u := range backendItem.URLs {
+		hosts = append(hosts, u.URL)
+	}
+	want := []string{"https://primary.example.com/", "https://xyz-01.example.com/", "https://xyz-02.example.com/"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Fatalf("expected %v, got %v", want, hosts)
+	}
+}
+
+// TestToBackendItem_DeduplicatesResolvedHostAgainstLiteral covers the
+// request's dedup scenario: a host appearing both literally in the URLs
+// array and as a resolver expansion result should only produce one URL.
+func TestToBackendItem_DeduplicatesResolvedHostAgainstLiteral(t *testing.T) {
+	item := &OPItem{
+		ID:       "item-2",
+		Title:    "Dedup Item",
+		Category: "LOGIN",
+		Vault:    OPVault{ID: "vault-2", Name: "Shared Vault"},
+		URLs: []OPURL{
+			{Label: "website", Href: "https://xyz-01.example.com/"},
+			{Label: "website", Href: "https://*.example.com/"},
+		},
+		Fields: []OPField{
+			{Label: "hosts", Value: "xyz-01\nxyz-02"},
+		},
+	}
+
+	backendItem := toBackendItem(item, nil, DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+	})
+
+	var hosts []string
+	for _, u := range backendItem.URLs {
+		hosts = append(hosts, u.URL)
+	}
+	want := []string{"https://xyz-01.example.com/", "https://xyz-02.example.com/"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Fatalf("expected deduplicated hosts %v, got %v", want, hosts)
+	}
+}
+
+// TestMapToSessions_ExpandedURLsPropagateItemIdentity covers the request's
+// propagation scenario: every session built from an expanded URL carries
+// the same ItemID/VaultID/Tags as the item it came from.
+func TestMapToSessions_ExpandedURLsPropagateItemIdentity(t *testing.T) {
+	item := &OPItem{
+		ID:       "item-3",
+		Title:    "Wildcard Tenants",
+		Category: "LOGIN",
+		Vault:    OPVault{ID: "vault-3", Name: "Shared Vault"},
+		URLs: []OPURL{
+			{Label: "website", Href: "https://*.example.com/"},
+		},
+		Fields: []OPField{
+			{ID: "username", Value: "svc"},
+			{Label: "hosts", Value: "xyz-01\nxyz-02"},
+		},
+		Tags: []string{"c8y", "shared"},
+	}
+
+	client := &Client{HostResolver: DNSResolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+	}}
+
+	sessions := client.mapToSessions(item, map[string]string{"vault-3": "Shared Vault"})
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 expanded sessions, got %d", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.ItemID != "item-3" {
+			t.Errorf("expected ItemID %q, got %q", "item-3", s.ItemID)
+		}
+		if s.VaultID != "vault-3" {
+			t.Errorf("expected VaultID %q, got %q", "vault-3", s.VaultID)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package onepassword
 
 import (
 	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/config"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
 )
 
 func TestMapToSessions_MultipleURLsInArray(t *testing.T) {
@@ -134,3 +137,66 @@ func TestActualMultiURL(t *testing.T) {
 		t.Errorf("Primary URL not found in sessions")
 	}
 }
+
+// TestMapToSessions_MultipleURLs_URLRuleNaming extends the multi-URL
+// coverage above to confirm a configured URL rule can classify the
+// environment, add tags, and override the name per-URL.
+func TestMapToSessions_MultipleURLs_URLRuleNaming(t *testing.T) {
+	core.SetURLRuleEngine(config.NewURLRuleEngine([]config.URLRule{
+		{Match: `https://staging\.example\.com`, Environment: "staging", Tags: []string{"non-prod"}, NameTemplate: "Staging Tenant"},
+	}))
+	t.Cleanup(func() { core.SetURLRuleEngine(nil) })
+
+	item := &OPItem{
+		ID:       "test123",
+		Title:    "Test Item",
+		Category: "LOGIN",
+		Vault: OPVault{
+			ID:   "vault123",
+			Name: "TestVault",
+		},
+		URLs: []OPURL{
+			{Label: "Production", Primary: true, Href: "https://prod.example.com"},
+			{Label: "Staging", Primary: false, Href: "https://staging.example.com"},
+		},
+		Fields: []OPField{
+			{ID: "username", Value: "testuser"},
+			{ID: "password", Value: "testpass"},
+		},
+		Tags: []string{"c8y"},
+	}
+
+	vaults := map[string]string{"vault123": "TestVault"}
+	backendItem := toBackendItem(item, vaults, NoopResolver{})
+	sessions := core.MapToSessions(backendItem.Item, backendItem.Fields, backendItem.URLs, backendItem.VaultName, nil)
+
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	var staging *core.CumulocitySession
+	for _, s := range sessions {
+		if s.Host == "https://staging.example.com" {
+			staging = s
+		}
+	}
+	if staging == nil {
+		t.Fatalf("expected a session for the staging URL")
+	}
+	if staging.Environment != "staging" {
+		t.Errorf("expected environment %q, got %q", "staging", staging.Environment)
+	}
+	if staging.Name != "Staging Tenant" {
+		t.Errorf("expected rule-driven name %q, got %q", "Staging Tenant", staging.Name)
+	}
+
+	foundTag := false
+	for _, tag := range staging.Tags {
+		if tag == "non-prod" {
+			foundTag = true
+		}
+	}
+	if !foundTag {
+		t.Errorf("expected staging session to have the rule's non-prod tag, got %v", staging.Tags)
+	}
+}
@@ -0,0 +1,99 @@
+package onepassword
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// RecoverableError wraps an error from an `op` invocation, recording
+// whether the same command is worth retrying. It mirrors the shape of
+// Nomad's structs.RecoverableError: Error() and Unwrap() defer to the
+// wrapped error, so callers that only care about the underlying failure
+// can keep using errors.Is/As against it.
+type RecoverableError struct {
+	Err         error
+	Recoverable bool
+}
+
+func (e *RecoverableError) Error() string { return e.Err.Error() }
+func (e *RecoverableError) Unwrap() error { return e.Err }
+
+// NewRecoverableError wraps err, recording whether retrying might succeed.
+// Returns nil if err is nil, so callers can write
+// "return NewRecoverableError(err, recoverable)" unconditionally.
+func NewRecoverableError(err error, recoverable bool) error {
+	if err == nil {
+		return nil
+	}
+	return &RecoverableError{Err: err, Recoverable: recoverable}
+}
+
+// IsRecoverable reports whether err (or anything it wraps) is a
+// RecoverableError with Recoverable set.
+func IsRecoverable(err error) bool {
+	var re *RecoverableError
+	return errors.As(err, &re) && re.Recoverable
+}
+
+// permanentOpStderr matches `op` stderr output for conditions retrying can
+// never fix: bad credentials, a vault/item that genuinely doesn't exist or
+// is ambiguous, or an authorization failure.
+var permanentOpStderr = regexp.MustCompile(`(?i)not signed in|isn'?t a vault in this account|more than one item matches|not authorized|authentication failed|invalid[^.]*credentials`)
+
+// transientOpStderr matches `op` stderr output for conditions likely to
+// clear up on their own: the 1Password desktop app or daemon being briefly
+// unreachable, or a dropped network connection.
+var transientOpStderr = regexp.MustCompile(`(?i)couldn'?t connect to (the )?desktop app|broken pipe|connection refused|connection reset|i/o timeout|temporary failure`)
+
+// classifyOpError wraps err as a RecoverableError based on op's stderr
+// output: a known-permanent condition is never recoverable, a
+// known-transient one always is, and anything unrecognized defaults to
+// unrecoverable -- a surprising, un-classified failure should surface
+// immediately rather than retrying blindly against an unfamiliar error.
+func classifyOpError(err error, stderr string) error {
+	if err == nil {
+		return nil
+	}
+	if permanentOpStderr.MatchString(stderr) {
+		return NewRecoverableError(err, false)
+	}
+	if transientOpStderr.MatchString(stderr) {
+		return NewRecoverableError(err, true)
+	}
+	return NewRecoverableError(err, false)
+}
+
+// opExecMaxAttempts and opExecBaseDelay bound the retry loop withOpRetry
+// applies to recoverable errors: up to 3 retries (4 attempts total), with
+// delay doubling from opExecBaseDelay each time.
+const (
+	opExecMaxAttempts = 4
+	opExecBaseDelay   = 200 * time.Millisecond
+)
+
+// withOpRetry runs attempt repeatedly (up to opExecMaxAttempts times total)
+// as long as it returns a recoverable error (see classifyOpError), waiting
+// opExecBaseDelay*2^n between tries. An unrecoverable error, or success,
+// returns immediately.
+func withOpRetry(attempt func() error) error {
+	var lastErr error
+	for n := 0; n < opExecMaxAttempts; n++ {
+		if n > 0 {
+			delay := opExecBaseDelay * time.Duration(uint(1)<<(n-1))
+			slog.Debug("retrying op command", "attempt", n+1, "delay", delay, "error", lastErr)
+			time.Sleep(delay)
+		}
+
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		if !IsRecoverable(err) {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
@@ -0,0 +1,94 @@
+package onepassword
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyOpError_PermanentIsNotRecoverable(t *testing.T) {
+	cases := []string{
+		"[ERROR] 2024/01/01 not signed in",
+		"[ERROR] \"Production\" isn't a vault in this account",
+		"[ERROR] more than one item matches \"c8y\"",
+		"[ERROR] you are not authorized to access this vault",
+	}
+	for _, stderr := range cases {
+		err := classifyOpError(errors.New("exit status 1"), stderr)
+		if IsRecoverable(err) {
+			t.Errorf("classifyOpError(%q) = recoverable, want unrecoverable", stderr)
+		}
+	}
+}
+
+func TestClassifyOpError_TransientIsRecoverable(t *testing.T) {
+	cases := []string{
+		"[ERROR] couldn't connect to the desktop app",
+		"write: broken pipe",
+		"dial tcp: connection refused",
+		"context deadline exceeded (i/o timeout)",
+	}
+	for _, stderr := range cases {
+		err := classifyOpError(errors.New("exit status 1"), stderr)
+		if !IsRecoverable(err) {
+			t.Errorf("classifyOpError(%q) = unrecoverable, want recoverable", stderr)
+		}
+	}
+}
+
+func TestClassifyOpError_UnrecognizedDefaultsToUnrecoverable(t *testing.T) {
+	err := classifyOpError(errors.New("exit status 1"), "some unfamiliar failure")
+	if IsRecoverable(err) {
+		t.Error("expected an unrecognized stderr message to default to unrecoverable")
+	}
+}
+
+func TestClassifyOpError_NilErrStaysNil(t *testing.T) {
+	if err := classifyOpError(nil, "not signed in"); err != nil {
+		t.Errorf("classifyOpError(nil, ...) = %v, want nil", err)
+	}
+}
+
+func TestWithOpRetry_StopsAtFirstUnrecoverableError(t *testing.T) {
+	attempts := 0
+	err := withOpRetry(func() error {
+		attempts++
+		return NewRecoverableError(errors.New("not signed in"), false)
+	})
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt for an unrecoverable error, got %d", attempts)
+	}
+	if IsRecoverable(err) {
+		t.Error("expected the returned error to stay unrecoverable")
+	}
+}
+
+func TestWithOpRetry_RetriesRecoverableUntilSuccess(t *testing.T) {
+	attempts := 0
+	err := withOpRetry(func() error {
+		attempts++
+		if attempts < 3 {
+			return NewRecoverableError(errors.New("couldn't connect to desktop app"), true)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withOpRetry() error = %v, want nil after eventual success", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts before success, got %d", attempts)
+	}
+}
+
+func TestWithOpRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	err := withOpRetry(func() error {
+		attempts++
+		return NewRecoverableError(errors.New("couldn't connect to desktop app"), true)
+	})
+	if attempts != opExecMaxAttempts {
+		t.Errorf("expected %d attempts, got %d", opExecMaxAttempts, attempts)
+	}
+	if err == nil {
+		t.Error("expected the last recoverable error to be returned once attempts are exhausted")
+	}
+}
@@ -0,0 +1,162 @@
+package onepassword
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// SaveOptions controls how SaveSession writes a session back to 1Password.
+type SaveOptions struct {
+	// DryRun prints the "op item create/edit" invocation instead of running
+	// it, so a provisioning script can be previewed before it touches a
+	// real vault.
+	DryRun bool
+
+	// Redact masks Password and TOTPSecret in DryRun's printed invocation.
+	// The actual "op" invocation (and its debug log line) always masks
+	// them regardless of Redact; this only affects what DryRun shows.
+	Redact bool
+}
+
+// redactedPlaceholder replaces a secret value in logged or dry-run-printed
+// `op` arguments.
+const redactedPlaceholder = "***"
+
+// SaveSession creates or updates the 1Password Login item backing session:
+// updating the item named by session.SessionURI if it's already an
+// "op://vault/item" URI, creating a new item in c.Vault otherwise.
+// username, password, the tenant custom field, the TOTP field, and the
+// session's URL are all written from session's fields; empty fields are
+// left unset rather than clearing any existing value.
+func (c *Client) SaveSession(session *core.CumulocitySession, opts SaveOptions) error {
+	if session == nil {
+		return fmt.Errorf("cannot save a nil session")
+	}
+
+	args, verb, err := c.saveArgs(session)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Printf("op %s\n", strings.Join(redactArgs(args, opts.Redact), " "))
+		return nil
+	}
+
+	return withOpRetry(func() error {
+		start := time.Now()
+		slog.Debug("op command", "command", "op "+strings.Join(redactArgs(args, true), " "))
+
+		cmd := exec.Command("op", args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		slog.Debug("op command completed", "duration_ms", time.Since(start).Milliseconds())
+		if err != nil {
+			return classifyOpError(fmt.Errorf("failed to %s 1Password item: %w", verb, err), stderr.String())
+		}
+		return nil
+	})
+}
+
+// saveArgs builds the "op item create" or "op item edit" invocation for
+// session: edit when session.SessionURI already names an existing
+// "op://vault/item", create (in c.Vault) otherwise. verb is "create" or
+// "edit", for error messages.
+func (c *Client) saveArgs(session *core.CumulocitySession) (args []string, verb string, err error) {
+	fields, err := saveFieldArgs(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if session.SessionURI != "" {
+		vault, item, err := ParseOPURI(session.SessionURI)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid session URI %q: %w", session.SessionURI, err)
+		}
+		args = append([]string{"item", "edit", item, "--vault", vault}, fields...)
+		if session.Host != "" {
+			args = append(args, "--url", session.Host)
+		}
+		return args, "edit", nil
+	}
+
+	vault := c.Vault
+	if vault == "" {
+		return nil, "", fmt.Errorf("cannot create a new item: session has no SessionURI and the client has no --vault set")
+	}
+
+	title := session.ItemName
+	if title == "" {
+		title = session.Name
+	}
+	if title == "" {
+		return nil, "", fmt.Errorf("cannot create a new item: session has neither ItemName nor Name to use as its title")
+	}
+
+	args = []string{"item", "create", "--category", "Login", "--title", title, "--vault", vault}
+	if session.Host != "" {
+		args = append(args, "--url", session.Host)
+	}
+	args = append(args, fields...)
+	return args, "create", nil
+}
+
+// saveFieldArgs builds the "key=value" assignment arguments for session's
+// username, password, tenant, and TOTP secret -- the same fields
+// extractFields reads back out of an item. A field left empty in session is
+// simply omitted, rather than assigned an empty value that would clear it.
+// Password and TOTPSecret are rejected outright if they're still the
+// redactedPlaceholder mask: that means session came from an unrevealed
+// "list"/"get" output piped straight into "save" without --reveal, and
+// writing "***" back to 1Password would silently clobber the real secret.
+func saveFieldArgs(session *core.CumulocitySession) ([]string, error) {
+	if session.Password == redactedPlaceholder {
+		return nil, fmt.Errorf("refusing to save: password is %q, the masked placeholder for an unrevealed session -- re-run with --reveal", redactedPlaceholder)
+	}
+	if session.TOTPSecret == redactedPlaceholder {
+		return nil, fmt.Errorf("refusing to save: totp secret is %q, the masked placeholder for an unrevealed session -- re-run with --reveal", redactedPlaceholder)
+	}
+
+	var args []string
+	if session.Username != "" {
+		args = append(args, "username="+session.Username)
+	}
+	if session.Password != "" {
+		args = append(args, "password="+session.Password)
+	}
+	if session.Tenant != "" {
+		args = append(args, "tenant[text]="+session.Tenant)
+	}
+	if session.TOTPSecret != "" {
+		args = append(args, "totp[otp]="+session.TOTPSecret)
+	}
+	return args, nil
+}
+
+// redactArgs copies args, replacing the value half of any "key=value"
+// assignment that carries a secret (password, totp) with
+// redactedPlaceholder when redact is true.
+func redactArgs(args []string, redact bool) []string {
+	if !redact {
+		return args
+	}
+
+	out := make([]string, len(args))
+	for i, arg := range args {
+		key, _, ok := strings.Cut(arg, "=")
+		if ok && (key == "password" || key == "totp[otp]") {
+			out[i] = key + "=" + redactedPlaceholder
+			continue
+		}
+		out[i] = arg
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package onepassword
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func TestClient_saveArgs_Create(t *testing.T) {
+	client := NewClient("Employee")
+	session := &core.CumulocitySession{
+		ItemName: "my-tenant",
+		Host:     "https://my-tenant.eu-latest.cumulocity.com",
+		Username: "admin",
+		Password: "s3cr3t",
+		Tenant:   "t12345",
+	}
+
+	args, verb, err := client.saveArgs(session)
+	if err != nil {
+		t.Fatalf("saveArgs() error = %v", err)
+	}
+	if verb != "create" {
+		t.Errorf("verb = %q, want create", verb)
+	}
+
+	want := []string{
+		"item", "create", "--category", "Login", "--title", "my-tenant", "--vault", "Employee",
+		"--url", "https://my-tenant.eu-latest.cumulocity.com",
+		"username=admin", "password=s3cr3t", "tenant[text]=t12345",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("saveArgs() = %v, want %v", args, want)
+	}
+}
+
+func TestClient_saveArgs_Update(t *testing.T) {
+	client := NewClient("")
+	session := &core.CumulocitySession{
+		SessionURI: "op://Employee/my-tenant",
+		Username:   "admin",
+		TOTPSecret: "JBSWY3DPEHPK3PXP",
+	}
+
+	args, verb, err := client.saveArgs(session)
+	if err != nil {
+		t.Fatalf("saveArgs() error = %v", err)
+	}
+	if verb != "edit" {
+		t.Errorf("verb = %q, want edit", verb)
+	}
+
+	want := []string{
+		"item", "edit", "my-tenant", "--vault", "Employee",
+		"username=admin", "totp[otp]=JBSWY3DPEHPK3PXP",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("saveArgs() = %v, want %v", args, want)
+	}
+}
+
+func TestClient_saveArgs_CreateRequiresVaultAndTitle(t *testing.T) {
+	client := NewClient("")
+	if _, _, err := client.saveArgs(&core.CumulocitySession{ItemName: "x"}); err == nil {
+		t.Error("expected an error when creating without a vault")
+	}
+
+	client = NewClient("Employee")
+	if _, _, err := client.saveArgs(&core.CumulocitySession{}); err == nil {
+		t.Error("expected an error when creating without a title")
+	}
+}
+
+func TestClient_saveArgs_RejectsMaskedPassword(t *testing.T) {
+	client := NewClient("")
+	session := &core.CumulocitySession{SessionURI: "op://Employee/my-tenant", Password: redactedPlaceholder}
+	if _, _, err := client.saveArgs(session); err == nil {
+		t.Error("expected an error when password is the masked placeholder")
+	}
+}
+
+func TestClient_saveArgs_RejectsMaskedTOTPSecret(t *testing.T) {
+	client := NewClient("")
+	session := &core.CumulocitySession{SessionURI: "op://Employee/my-tenant", TOTPSecret: redactedPlaceholder}
+	if _, _, err := client.saveArgs(session); err == nil {
+		t.Error("expected an error when totp secret is the masked placeholder")
+	}
+}
+
+func TestClient_saveArgs_InvalidURI(t *testing.T) {
+	client := NewClient("")
+	session := &core.CumulocitySession{SessionURI: "not-a-uri"}
+	if _, _, err := client.saveArgs(session); err == nil {
+		t.Error("expected an error for an invalid session URI")
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := []string{"username=admin", "password=s3cr3t", "totp[otp]=JBSWY3DPEHPK3PXP", "--vault", "Employee"}
+
+	got := redactArgs(args, true)
+	want := []string{"username=admin", "password=" + redactedPlaceholder, "totp[otp]=" + redactedPlaceholder, "--vault", "Employee"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactArgs(redact=true) = %v, want %v", got, want)
+	}
+
+	if got := redactArgs(args, false); !reflect.DeepEqual(got, args) {
+		t.Errorf("redactArgs(redact=false) = %v, want unchanged %v", got, args)
+	}
+}
+
+func TestSaveSession_NilSession(t *testing.T) {
+	client := NewClient("Employee")
+	if err := client.SaveSession(nil, SaveOptions{}); err == nil {
+		t.Error("expected an error for a nil session")
+	}
+}
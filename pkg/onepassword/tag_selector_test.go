@@ -0,0 +1,44 @@
+package onepassword
+
+import "testing"
+
+func TestClientTagSelector_LegacyBareList(t *testing.T) {
+	client := NewClient("test-vault", "c8y", "production")
+
+	sel, err := client.tagSelector()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.IsLegacyBareList() {
+		t.Errorf("expected a plain comma-separated tag list to be the legacy bare form")
+	}
+	if !sel.Matches([]string{"production"}) {
+		t.Errorf("expected OR match on 'production'")
+	}
+}
+
+func TestClientTagSelector_SelectorGrammar(t *testing.T) {
+	client := NewClient("test-vault", "env=prod,!deprecated")
+
+	sel, err := client.tagSelector()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sel.IsLegacyBareList() {
+		t.Errorf("expected selector-grammar tags to not be treated as the legacy bare form")
+	}
+	if !sel.Matches([]string{"env=prod"}) {
+		t.Errorf("expected match on env=prod")
+	}
+	if sel.Matches([]string{"env=prod", "deprecated"}) {
+		t.Errorf("expected no match when deprecated tag is present")
+	}
+}
+
+func TestClientTagSelector_InvalidExpression(t *testing.T) {
+	client := NewClient("test-vault", "env in (prod")
+
+	if _, err := client.tagSelector(); err == nil {
+		t.Errorf("expected an error for an unterminated selector expression")
+	}
+}
@@ -0,0 +1,151 @@
+// Package prefs persists per-user picker preferences (favorites, last-used
+// timestamps, last filter query, sort order) to
+// $XDG_CONFIG_HOME/c8y-session-1password/prefs.json.
+package prefs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+// Sort order values cycled through by the picker's `s` binding.
+const (
+	SortByHost      = "host"
+	SortByLastUsed  = "last-used"
+	SortByFavorite  = "favorite"
+	defaultFileName = "prefs.json"
+)
+
+// Prefs holds everything the picker remembers between invocations.
+type Prefs struct {
+	Favorites map[string]bool      `json:"favorites,omitempty"`
+	LastUsed  map[string]time.Time `json:"lastUsed,omitempty"`
+	LastQuery string               `json:"lastQuery,omitempty"`
+	SortOrder string               `json:"sortOrder,omitempty"`
+}
+
+// Key returns the stable identity used to track a session across
+// invocations. Sessions from multi-URL items share an ItemID but differ by
+// Host, so both are part of the key.
+func Key(session *core.CumulocitySession) string {
+	return session.ItemID + "|" + session.Host
+}
+
+func path() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "c8y-session-1password", defaultFileName), nil
+}
+
+// defaultPrefs returns empty (but usable) preferences.
+func defaultPrefs() *Prefs {
+	return &Prefs{
+		Favorites: make(map[string]bool),
+		LastUsed:  make(map[string]time.Time),
+		SortOrder: SortByHost,
+	}
+}
+
+// Load reads prefs.json, returning defaultPrefs() if it doesn't exist or
+// fails to parse. A missing prefs file is not an error.
+func Load() *Prefs {
+	file, err := path()
+	if err != nil {
+		return defaultPrefs()
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return defaultPrefs()
+	}
+
+	p := defaultPrefs()
+	if err := json.Unmarshal(data, p); err != nil {
+		return defaultPrefs()
+	}
+
+	if p.Favorites == nil {
+		p.Favorites = make(map[string]bool)
+	}
+	if p.LastUsed == nil {
+		p.LastUsed = make(map[string]time.Time)
+	}
+	if p.SortOrder == "" {
+		p.SortOrder = SortByHost
+	}
+
+	return p
+}
+
+// Save writes prefs.json, creating the config directory if needed.
+func (p *Prefs) Save() error {
+	file, err := path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o600)
+}
+
+// IsFavorite reports whether session has been favorited.
+func (p *Prefs) IsFavorite(session *core.CumulocitySession) bool {
+	return p.Favorites[Key(session)]
+}
+
+// ToggleFavorite flips the favorite state for session.
+func (p *Prefs) ToggleFavorite(session *core.CumulocitySession) {
+	key := Key(session)
+	if p.Favorites[key] {
+		delete(p.Favorites, key)
+		return
+	}
+	if p.Favorites == nil {
+		p.Favorites = make(map[string]bool)
+	}
+	p.Favorites[key] = true
+}
+
+// BumpLastUsed records now as the last-used time for session.
+func (p *Prefs) BumpLastUsed(session *core.CumulocitySession) {
+	if p.LastUsed == nil {
+		p.LastUsed = make(map[string]time.Time)
+	}
+	p.LastUsed[Key(session)] = time.Now()
+}
+
+// LastUsedTime returns the last-used time for session, or the zero time if
+// it has never been used.
+func (p *Prefs) LastUsedTime(session *core.CumulocitySession) time.Time {
+	return p.LastUsed[Key(session)]
+}
+
+// NextSortOrder cycles host -> last-used -> favorite -> host.
+func NextSortOrder(current string) string {
+	switch current {
+	case SortByHost:
+		return SortByLastUsed
+	case SortByLastUsed:
+		return SortByFavorite
+	default:
+		return SortByHost
+	}
+}
@@ -0,0 +1,58 @@
+package prefs
+
+import (
+	"testing"
+
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+)
+
+func TestToggleFavorite(t *testing.T) {
+	p := defaultPrefs()
+	session := &core.CumulocitySession{ItemID: "item1", Host: "https://test.cumulocity.com"}
+
+	if p.IsFavorite(session) {
+		t.Fatal("expected session to start unfavorited")
+	}
+
+	p.ToggleFavorite(session)
+	if !p.IsFavorite(session) {
+		t.Fatal("expected session to be favorited after toggle")
+	}
+
+	p.ToggleFavorite(session)
+	if p.IsFavorite(session) {
+		t.Fatal("expected session to be unfavorited after second toggle")
+	}
+}
+
+func TestBumpLastUsed(t *testing.T) {
+	p := defaultPrefs()
+	session := &core.CumulocitySession{ItemID: "item1", Host: "https://test.cumulocity.com"}
+
+	if !p.LastUsedTime(session).IsZero() {
+		t.Fatal("expected zero last-used time before first use")
+	}
+
+	p.BumpLastUsed(session)
+	if p.LastUsedTime(session).IsZero() {
+		t.Fatal("expected non-zero last-used time after BumpLastUsed")
+	}
+}
+
+func TestNextSortOrder(t *testing.T) {
+	tests := []struct {
+		current  string
+		expected string
+	}{
+		{SortByHost, SortByLastUsed},
+		{SortByLastUsed, SortByFavorite},
+		{SortByFavorite, SortByHost},
+		{"", SortByHost},
+	}
+
+	for _, tt := range tests {
+		if got := NextSortOrder(tt.current); got != tt.expected {
+			t.Errorf("NextSortOrder(%q) = %q, expected %q", tt.current, got, tt.expected)
+		}
+	}
+}
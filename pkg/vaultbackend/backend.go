@@ -0,0 +1,85 @@
+// Package vaultbackend implements a HashiCorp Vault secrets-engine plugin
+// that exposes Cumulocity sessions from 1Password as read-only Vault
+// secrets. It is loaded via `c8y-session-1password vault server` and wraps
+// the same pkg/onepassword.Client used by the CLI's RunE.
+package vaultbackend
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+const backendHelp = `
+The c8y-session-1password secrets engine reads Cumulocity sessions from
+1Password and exposes them as Vault secrets, so they can be consumed the
+same way as any other secret backend.
+
+Configure the backend under "config" with the default vault, tag filter,
+reveal policy, and 1Password authentication mode. Read "sessions" to list
+all matching sessions, "sessions/<name>" for a single session by name, and
+"sessions/<vault>/<item>" (or, equivalently, "uri/op:/<vault>/<item>") to
+resolve a specific op:// item directly.
+`
+
+// backend wraps framework.Backend; each read builds its onepassword client
+// from the stored configuration (see client). A sync.RWMutex serializes
+// config writes against reads, since Vault may serve requests concurrently.
+type backend struct {
+	*framework.Backend
+
+	lock sync.RWMutex
+}
+
+// Factory is the plugin entry point registered with plugin.Serve.
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := newBackend()
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func newBackend() *backend {
+	var b backend
+
+	b.Backend = &framework.Backend{
+		Help: strings.TrimSpace(backendHelp),
+		Paths: framework.PathAppend(
+			[]*framework.Path{b.pathConfig()},
+			b.pathSessions(),
+			b.pathURI(),
+		),
+		PathsSpecial: &logical.Paths{
+			SealWrapStorage: []string{"config"},
+		},
+		Secrets:     []*framework.Secret{},
+		BackendType: logical.TypeLogical,
+	}
+
+	return &b
+}
+
+// client builds a pkg/onepassword.Client from the backend's stored
+// configuration, applying the configured auth mode's environment variables
+// first so the `op` CLI picks them up the same way it would standalone.
+func (b *backend) client(ctx context.Context, storage logical.Storage) (*onepassword.Client, error) {
+	cfg, err := b.config(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.applyAuthEnv()
+
+	// Default to the "c8y" tag when none is configured, same as the CLI.
+	tags := cfg.Tags
+	if len(tags) == 0 {
+		tags = []string{"c8y"}
+	}
+
+	return onepassword.NewClient(cfg.DefaultVault, tags...), nil
+}
@@ -0,0 +1,172 @@
+package vaultbackend
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// Auth modes accepted by the "auth_mode" config field. These mirror the
+// authentication options documented in the root command's long help.
+const (
+	AuthModeCLI            = "cli"
+	AuthModeServiceAccount = "service_account"
+	AuthModeConnect        = "connect"
+)
+
+const configStorageKey = "config"
+
+// config holds the backend's persisted settings, written and read through
+// the standard Vault plugin config/ path.
+type config struct {
+	DefaultVault string   `json:"default_vault"`
+	Tags         []string `json:"tags"`
+	Reveal       bool     `json:"reveal"`
+	AuthMode     string   `json:"auth_mode"`
+	ServiceToken string   `json:"service_account_token"`
+	ConnectHost  string   `json:"connect_host"`
+	ConnectToken string   `json:"connect_token"`
+}
+
+// applyAuthEnv exports the environment variables the `op` CLI expects for
+// the configured auth mode, matching the same variables documented for
+// standalone use (OP_SERVICE_ACCOUNT_TOKEN, OP_CONNECT_HOST/TOKEN).
+func (c *config) applyAuthEnv() {
+	switch c.AuthMode {
+	case AuthModeServiceAccount:
+		os.Setenv("OP_SERVICE_ACCOUNT_TOKEN", c.ServiceToken)
+	case AuthModeConnect:
+		os.Setenv("OP_CONNECT_HOST", c.ConnectHost)
+		os.Setenv("OP_CONNECT_TOKEN", c.ConnectToken)
+	}
+}
+
+func (b *backend) pathConfig() *framework.Path {
+	return &framework.Path{
+		Pattern: configStorageKey,
+
+		Fields: map[string]*framework.FieldSchema{
+			"default_vault": {
+				Type:        framework.TypeString,
+				Description: "Default 1Password vault name or ID to search when none is specified on a read.",
+			},
+			"tags": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Tags sessions must carry to be returned (defaults to [\"c8y\"]).",
+			},
+			"reveal": {
+				Type:        framework.TypeBool,
+				Description: "Whether reads include plaintext passwords/TOTP secrets instead of \"***\".",
+			},
+			"auth_mode": {
+				Type:        framework.TypeString,
+				Description: "1Password auth mode: \"cli\" (rely on an already signed-in op CLI), \"service_account\", or \"connect\".",
+				Default:     AuthModeCLI,
+			},
+			"service_account_token": {
+				Type:        framework.TypeString,
+				Description: "Service account token, used when auth_mode is \"service_account\".",
+			},
+			"connect_host": {
+				Type:        framework.TypeString,
+				Description: "1Password Connect host, used when auth_mode is \"connect\".",
+			},
+			"connect_token": {
+				Type:        framework.TypeString,
+				Description: "1Password Connect token, used when auth_mode is \"connect\".",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigRead,
+				Summary:  "Read the backend's configuration.",
+			},
+			logical.CreateOperation: &framework.PathOperation{
+				Callback: b.pathConfigWrite,
+				Summary:  "Write the backend's configuration.",
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConfigWrite,
+				Summary:  "Write the backend's configuration.",
+			},
+		},
+
+		HelpSynopsis:    "Configure the default vault, tag filter, reveal policy, and 1Password auth mode.",
+		HelpDescription: "This endpoint configures the default vault/tags/reveal behavior used by the sessions and uri paths, along with how the backend authenticates to 1Password.",
+	}
+}
+
+func (b *backend) config(ctx context.Context, storage logical.Storage) (*config, error) {
+	entry, err := storage.Get(ctx, configStorageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config{AuthMode: AuthModeCLI}
+	if entry == nil {
+		return cfg, nil
+	}
+
+	if err := entry.DecodeJSON(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"default_vault": cfg.DefaultVault,
+			"tags":          cfg.Tags,
+			"reveal":        cfg.Reveal,
+			"auth_mode":     cfg.AuthMode,
+			"connect_host":  cfg.ConnectHost,
+			// Tokens are intentionally omitted from reads.
+		},
+	}, nil
+}
+
+func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	cfg := &config{
+		DefaultVault: data.Get("default_vault").(string),
+		Tags:         data.Get("tags").([]string),
+		Reveal:       data.Get("reveal").(bool),
+		AuthMode:     strings.ToLower(data.Get("auth_mode").(string)),
+		ServiceToken: data.Get("service_account_token").(string),
+		ConnectHost:  data.Get("connect_host").(string),
+		ConnectToken: data.Get("connect_token").(string),
+	}
+
+	switch cfg.AuthMode {
+	case AuthModeCLI, AuthModeServiceAccount, AuthModeConnect:
+	default:
+		return logical.ErrorResponse("invalid auth_mode %q: expected one of cli, service_account, connect", cfg.AuthMode), nil
+	}
+
+	entry, err := logical.StorageEntryJSON(configStorageKey, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
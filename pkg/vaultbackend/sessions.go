@@ -0,0 +1,192 @@
+package vaultbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// pathSessions returns the read-only "sessions" (list) and "sessions/<name>"
+// (single session) paths, both backed by onepassword.Client.List().
+func (b *backend) pathSessions() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "sessions/?$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.pathSessionsList,
+					Summary:  "List the names of every session matching the configured vault and tags.",
+				},
+			},
+
+			HelpSynopsis: "List available Cumulocity sessions.",
+		},
+		{
+			Pattern: "sessions/" + framework.GenericNameRegex("name"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Session name, as returned by sessions (list).",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathSessionRead,
+					Summary:  "Read a single Cumulocity session by name.",
+				},
+			},
+
+			HelpSynopsis: "Read a single Cumulocity session by name.",
+		},
+		{
+			Pattern: "sessions/" + framework.GenericNameRegex("vault") + "/" + framework.GenericNameRegex("item"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"vault": {
+					Type:        framework.TypeString,
+					Description: "Vault name or ID, as in op://vault/item.",
+				},
+				"item": {
+					Type:        framework.TypeString,
+					Description: "Item ID or name, as in op://vault/item.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathSessionItemRead,
+					Summary:  "Read a single Cumulocity session by vault and item, bypassing the configured tag filter.",
+				},
+			},
+
+			HelpSynopsis: "Read a single session directly by vault and item, same as uri/op:/<vault>/<item>.",
+		},
+	}
+}
+
+func (b *backend) pathSessionsList(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	sessions, err := b.listSessions(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(sessions))
+	for i, session := range sessions {
+		names[i] = session.Name
+	}
+
+	return logical.ListResponse(names), nil
+}
+
+func (b *backend) pathSessionRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	name := data.Get("name").(string)
+
+	sessions, err := b.listSessions(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.Name == name {
+			return sessionResponse(ctx, b, req.Storage, session), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// pathSessionItemRead resolves a session directly by vault and item,
+// equivalent to uri/op:/<vault>/<item> but under the sessions/ namespace the
+// request body asks for.
+func (b *backend) pathSessionItemRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.readItem(ctx, req.Storage, data.Get("vault").(string), data.Get("item").(string))
+}
+
+// readItem fetches a single op://vault/item session and renders it as a
+// logical.Response, shared by pathSessionItemRead and pathURIRead.
+func (b *backend) readItem(ctx context.Context, storage logical.Storage, vault, item string) (*logical.Response, error) {
+	client, err := b.client(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.GetItem(vault, item)
+	if err != nil {
+		return nil, fmt.Errorf("resolving op://%s/%s: %w", vault, item, err)
+	}
+
+	return sessionResponse(ctx, b, storage, session), nil
+}
+
+// listSessions loads the configured client and lists matching sessions.
+func (b *backend) listSessions(ctx context.Context, storage logical.Storage) ([]*core.CumulocitySession, error) {
+	client, err := b.client(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := client.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// sessionResponse converts a session into a logical.Response, obfuscating
+// secrets unless the backend is configured with reveal = true. The TOTP code
+// is generated fresh from TOTPSecret rather than read off the session, since
+// List()/GetItem() don't populate it themselves (the CLI only does so at
+// output time, via its own populateTOTP helper).
+func sessionResponse(ctx context.Context, b *backend, storage logical.Storage, session *core.CumulocitySession) *logical.Response {
+	cfg, err := b.config(ctx, storage)
+	if err != nil {
+		return logical.ErrorResponse(err.Error())
+	}
+
+	totp := session.TOTP
+	if totp == "" && session.TOTPSecret != "" {
+		if code, err := onepassword.GetTOTPCodeFromSecret(session.TOTPSecret); err == nil {
+			totp = code
+		}
+	}
+
+	password, totpSecret := session.Password, session.TOTPSecret
+	if !cfg.Reveal {
+		password, totp, totpSecret = "***", "***", "***"
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"session_uri": session.SessionURI,
+			"name":        session.Name,
+			"host":        session.Host,
+			"username":    session.Username,
+			"password":    password,
+			"tenant":      session.Tenant,
+			"totp":        totp,
+			"totp_secret": totpSecret,
+			"item_id":     session.ItemID,
+			"item_name":   session.ItemName,
+			"vault_id":    session.VaultID,
+			"vault_name":  session.VaultName,
+			"tags":        session.Tags,
+		},
+	}
+}
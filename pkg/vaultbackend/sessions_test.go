@@ -0,0 +1,68 @@
+package vaultbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/pquerna/otp/totp"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/onepassword"
+)
+
+// writeTestConfig stores cfg directly, bypassing pathConfigWrite's field
+// defaulting so tests can set only the fields they care about.
+func writeTestConfig(t *testing.T, storage logical.Storage, cfg *config) {
+	t.Helper()
+
+	entry, err := logical.StorageEntryJSON(configStorageKey, cfg)
+	if err != nil {
+		t.Fatalf("building config entry: %v", err)
+	}
+	if err := storage.Put(context.Background(), entry); err != nil {
+		t.Fatalf("storage.Put() error: %v", err)
+	}
+}
+
+func TestSessionResponse_GeneratesFreshTOTP(t *testing.T) {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "test"})
+	if err != nil {
+		t.Fatalf("totp.Generate() error: %v", err)
+	}
+
+	session := &core.CumulocitySession{
+		Name:       "test-session",
+		TOTPSecret: key.Secret(),
+	}
+
+	storage := &logical.InmemStorage{}
+	writeTestConfig(t, storage, &config{Reveal: true})
+
+	resp := sessionResponse(context.Background(), &backend{}, storage, session)
+
+	got, _ := resp.Data["totp"].(string)
+	if got == "" {
+		t.Fatal("expected a freshly generated TOTP code, got empty string")
+	}
+
+	code, err := onepassword.GetTOTPCodeFromSecret(key.Secret())
+	if err != nil {
+		t.Fatalf("generating expected code: %v", err)
+	}
+	if got != code {
+		t.Errorf("totp = %q, want %q", got, code)
+	}
+}
+
+func TestSessionResponse_MasksTOTPWhenNotRevealed(t *testing.T) {
+	session := &core.CumulocitySession{
+		Name:       "test-session",
+		TOTPSecret: "JBSWY3DPEHPK3PXP",
+	}
+
+	resp := sessionResponse(context.Background(), &backend{}, &logical.InmemStorage{}, session)
+
+	if got := resp.Data["totp"]; got != "***" {
+		t.Errorf("totp = %v, want masked \"***\" when reveal is unset", got)
+	}
+}
@@ -0,0 +1,47 @@
+package vaultbackend
+
+import (
+	"context"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// pathURI returns the "uri/op:/<vault>/<item>" path, which resolves a
+// single op:// item directly via onepassword.Client.GetItem(), mirroring
+// the CLI's --uri flag. sessions/<vault>/<item> (see pathSessionItemRead)
+// resolves the same way, under the sessions/ namespace instead.
+func (b *backend) pathURI() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "uri/op:/" + framework.GenericNameRegex("vault") + "/" + framework.GenericNameRegex("item"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"vault": {
+					Type:        framework.TypeString,
+					Description: "Vault name or ID, as in op://vault/item.",
+				},
+				"item": {
+					Type:        framework.TypeString,
+					Description: "Item ID or name, as in op://vault/item.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathURIRead,
+					Summary:  "Resolve a single item by its op:// vault and item identifiers.",
+				},
+			},
+
+			HelpSynopsis: "Resolve a single op://vault/item session.",
+		},
+	}
+}
+
+func (b *backend) pathURIRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.readItem(ctx, req.Storage, data.Get("vault").(string), data.Get("item").(string))
+}
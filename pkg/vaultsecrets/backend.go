@@ -0,0 +1,171 @@
+// Package vaultsecrets implements core.SecretBackend against a HashiCorp
+// Vault KV v2 mount, so teams that standardize on Vault can reuse the same
+// session tooling as pkg/onepassword without a parallel fork. It is
+// unrelated to pkg/vaultbackend, which instead runs this project *as* a
+// Vault plugin; this package is a *client* of Vault.
+package vaultsecrets
+
+import (
+	"fmt"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core"
+	"github.com/thomaswinkler/c8y-session-1password/pkg/core/selector"
+)
+
+// Backend implements core.SecretBackend by reading Login-shaped secrets
+// from a Vault KV v2 mount. Session URIs use the "vault://<mount>/<path>"
+// scheme; a secret's "username", "password", "tenant", and "totp" string
+// fields are mapped the same way 1Password's item fields are, and its
+// "host" field becomes the session's (only) URL.
+type Backend struct {
+	client *vaultapi.Client
+	mount  string
+}
+
+// NewBackend returns a core.SecretBackend backed by the Vault KV v2 engine
+// mounted at mount, using client (already configured with its address and
+// token, e.g. via vaultapi.NewClient(vaultapi.DefaultConfig())).
+func NewBackend(client *vaultapi.Client, mount string) *Backend {
+	return &Backend{client: client, mount: strings.Trim(mount, "/")}
+}
+
+// Scheme implements core.SecretBackend.
+func (b *Backend) Scheme() string { return "vault" }
+
+// ListItems implements core.SecretBackend, recursively listing every secret
+// under the mount (or the mount named by vault, if given) and keeping only
+// those tags selects. tags is compiled with the same pkg/core/selector
+// grammar pkg/onepassword uses (key=value, negation, in/notin, OR-ed groups
+// via "|", and the legacy comma-separated-OR bare list), so both backends
+// honor identical --tags expressions.
+func (b *Backend) ListItems(vault string, tags []string) ([]core.BackendItem, error) {
+	mount := b.mountFor(vault)
+
+	tagSelector, err := selector.Parse(strings.Join(tags, ","))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tags selector: %w", err)
+	}
+
+	paths, err := b.listPaths(mount, "")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]core.BackendItem, 0, len(paths))
+	for _, path := range paths {
+		item, err := b.GetItem(mount, path)
+		if err != nil {
+			return nil, fmt.Errorf("reading vault secret %s/%s: %w", mount, path, err)
+		}
+		if len(tags) > 0 && !tagSelector.Matches(item.Item.Tags) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetItem implements core.SecretBackend.
+func (b *Backend) GetItem(vault, item string) (core.BackendItem, error) {
+	mount := b.mountFor(vault)
+
+	secret, err := b.client.Logical().Read(fmt.Sprintf("%s/data/%s", mount, item))
+	if err != nil {
+		return core.BackendItem{}, fmt.Errorf("reading vault secret %s/%s: %w", mount, item, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return core.BackendItem{}, fmt.Errorf("vault secret %s/%s not found", mount, item)
+	}
+
+	data, _ := secret.Data["data"].(map[string]interface{})
+	field := func(key string) string {
+		value, _ := data[key].(string)
+		return value
+	}
+
+	var tags []string
+	if raw := field("tags"); raw != "" {
+		for _, tag := range strings.Split(raw, ",") {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+	}
+
+	title := field("name")
+	if title == "" {
+		title = item
+	}
+
+	coreItem := core.Item{
+		ID:    item,
+		Title: title,
+		Tags:  tags,
+		Vault: core.Vault{ID: mount, Name: mount},
+	}
+	fields := core.ItemFields{
+		Username:   field("username"),
+		Password:   field("password"),
+		TOTPSecret: field("totp"),
+		Tenant:     field("tenant"),
+	}
+
+	var urls []core.URLSource
+	if host := field("host"); host != "" {
+		urls = append(urls, core.URLSource{URL: host, Primary: true, Source: "field"})
+	}
+
+	return core.BackendItem{Item: coreItem, Fields: fields, URLs: urls, VaultName: mount}, nil
+}
+
+// ResolveURI implements core.SecretBackend, parsing "vault://<mount>/<path>".
+func (b *Backend) ResolveURI(uri string) (vault, item string, err error) {
+	const prefix = "vault://"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", "", fmt.Errorf("invalid vault:// URI format: expected vault://mount/path, got %s", uri)
+	}
+
+	rest := strings.TrimPrefix(uri, prefix)
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid vault:// URI format: expected vault://mount/path, got %s", uri)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (b *Backend) mountFor(vault string) string {
+	if vault != "" {
+		return strings.Trim(vault, "/")
+	}
+	return b.mount
+}
+
+// listPaths recursively lists every leaf secret path under mount/prefix,
+// following KV v2's convention of suffixing folder entries with "/".
+func (b *Backend) listPaths(mount, prefix string) ([]string, error) {
+	secret, err := b.client.Logical().List(fmt.Sprintf("%s/metadata/%s", mount, prefix))
+	if err != nil {
+		return nil, fmt.Errorf("listing vault secrets under %s/%s: %w", mount, prefix, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+
+	keysRaw, _ := secret.Data["keys"].([]interface{})
+	var paths []string
+	for _, raw := range keysRaw {
+		key, _ := raw.(string)
+		full := prefix + key
+
+		if strings.HasSuffix(key, "/") {
+			nested, err := b.listPaths(mount, full)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, nested...)
+			continue
+		}
+		paths = append(paths, full)
+	}
+	return paths, nil
+}